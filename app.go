@@ -0,0 +1,123 @@
+package fiberopenapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/spec/option"
+)
+
+// AppGenerator is a Generator that also documents the routes registered
+// directly on its Fiber app, see FromApp.
+type AppGenerator interface {
+	Generator
+
+	// Operation returns the app route registered with the method and the
+	// Fiber path, to attach operation options to it.
+	// If there is no such route, Validate reports an error.
+	Operation(method, path string) Route
+
+	// NamedRoute returns the app route with the Fiber name, to attach
+	// operation options to it.
+	// If there is no such route, Validate reports an error.
+	NamedRoute(name string) Route
+}
+
+// FromApp creates an OpenAPI router on an existing Fiber app, like
+// NewRouterWithConfig, and documents the routes already registered on the
+// app, so that the app can adopt OpenAPI gradually.
+//
+// The app routes are documented with their path parameters and the security
+// of their middleware listed in Config.SecurityMiddleware, options are
+// attached afterwards with Operation and NamedRoute. Their handlers are left
+// as is, so request binding and response validation do not apply to them.
+//
+// The HEAD routes Fiber registers along with GET routes are not documented,
+// nor are CONNECT routes, which OpenAPI does not support.
+func FromApp(app *fiber.App, config Config, opts ...option.OpenAPIOption) AppGenerator {
+	// Routes of the app, before the router adds the docs routes.
+	appRoutes := app.GetRoutes(true)
+
+	r := NewRouterWithConfig(app, config, opts...).(*router)
+	g := &appGenerator{
+		router: r,
+		routes: make(map[string]*route),
+		names:  make(map[string]*route),
+	}
+
+	gets := make(map[string]bool)
+	for _, fr := range appRoutes {
+		if fr.Method == fiber.MethodGet {
+			gets[fr.Path] = true
+		}
+	}
+
+	var imported []*route
+	for _, fr := range appRoutes {
+		if fr.Method == fiber.MethodConnect || (fr.Method == fiber.MethodHead && gets[fr.Path]) {
+			continue
+		}
+		key := routeKey(fr.Method, fr.Path)
+		if _, dup := g.routes[key]; dup {
+			continue
+		}
+		rt := &route{
+			reg:    r.reg,
+			method: fr.Method,
+			path:   fr.Path,
+			name:   fr.Name,
+			opts:   r.security.operationOptions(fr.Handlers),
+			source: handlerLocation(fr.Handlers),
+		}
+		g.routes[key] = rt
+		if fr.Name != "" {
+			if _, dup := g.names[fr.Name]; !dup {
+				g.names[fr.Name] = rt
+			}
+		}
+		imported = append(imported, rt)
+	}
+	r.reg.update(func() {
+		r.group.routes = append(imported, r.group.routes...)
+	})
+
+	return g
+}
+
+var _ AppGenerator = (*appGenerator)(nil)
+
+// appGenerator is the router returned by FromApp, with the routes imported from the app.
+type appGenerator struct {
+	*router
+	routes map[string]*route // by method and path
+	names  map[string]*route
+}
+
+func (g *appGenerator) Operation(method, path string) Route {
+	method = strings.ToUpper(method)
+	if rt, ok := g.routes[routeKey(method, path)]; ok {
+		return rt
+	}
+	return g.missing(method, path, fmt.Errorf("%s %s: no route registered on the app", method, path))
+}
+
+func (g *appGenerator) NamedRoute(name string) Route {
+	if rt, ok := g.names[name]; ok {
+		return rt
+	}
+	return g.missing("", name, fmt.Errorf("no route named %q registered on the app", name))
+}
+
+// missing returns a route that documents nothing and reports err from Validate.
+func (g *appGenerator) missing(method, path string, err error) *route {
+	rt := &route{reg: g.reg, method: method, path: path, err: err, source: callerLocation()}
+	g.reg.update(func() {
+		g.group.routes = append(g.group.routes, rt)
+	})
+	return rt
+}
+
+func routeKey(method, path string) string {
+	return method + " " + path
+}
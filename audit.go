@@ -0,0 +1,238 @@
+package fiberopenapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi/internal/util"
+	"github.com/oaswrap/spec/option"
+)
+
+// AuditIssueKind is the kind of an issue reported by Audit.
+type AuditIssueKind string
+
+const (
+	// AuditUndocumentedRoute is a Fiber route that is not registered through the router.
+	AuditUndocumentedRoute AuditIssueKind = "undocumented-route"
+	// AuditMissingHandler is a documented operation with no Fiber route.
+	AuditMissingHandler AuditIssueKind = "missing-handler"
+	// AuditPathParamMismatch is a path parameter of a request structure
+	// that is not a parameter of the route path.
+	AuditPathParamMismatch AuditIssueKind = "path-param-mismatch"
+	// AuditUnsupportedMethod is a route whose method OpenAPI can not
+	// document, such as CONNECT, left out of the specification.
+	AuditUnsupportedMethod AuditIssueKind = "unsupported-method"
+)
+
+// AuditIssue is an inconsistency between the Fiber routes and the documented operations.
+type AuditIssue struct {
+	Kind AuditIssueKind
+	// Method and Path are the method and the Fiber path of the route.
+	Method string
+	Path   string
+	// Message describes the issue.
+	Message string
+	// Source is the location of the route registration, or of the route
+	// handler for the routes registered directly on Fiber.
+	Source SourceLocation
+}
+
+func (i AuditIssue) String() string {
+	s := strings.TrimSpace(i.Method+" "+i.Path) + ": " + i.Message
+	if i.Source.File != "" {
+		s = i.Source.String() + ": " + s
+	}
+	return s
+}
+
+// auditRoute is a route of the route tree with its full Fiber path.
+type auditRoute struct {
+	*route
+	path string
+	// unsupported is the error of a route whose method OpenAPI can not document.
+	unsupported error
+}
+
+// Audit compares the routes registered on the Fiber app with the operations
+// of the router, mounted generators included.
+//
+// It reports the Fiber routes registered without the router, the documented
+// routes that have no Fiber route, and the path parameters declared by the
+// request structures that are not in the route path, along with the routes
+// whose method OpenAPI can not document. Hidden routes are known to the
+// router, so they are not reported as undocumented.
+//
+// The Fiber routes are only compared when the router is created on a
+// *fiber.App, since a fiber.Group does not give access to the routes.
+func (r *router) Audit() []AuditIssue {
+	routes, docs := r.reg.auditRoutes("")
+
+	var issues []AuditIssue
+	documented := make(map[string]bool, len(routes))
+	for _, rt := range routes {
+		documented[auditKey(rt.method, rt.path)] = true
+	}
+
+	app, _ := r.root.fiberRouter.(*fiber.App)
+	registered := make(map[string]bool)
+	if app != nil {
+		// The routes of the mounted apps are added to the app on startup.
+		app.Handler()
+
+		gets := make(map[string]bool)
+		for _, fr := range app.GetRoutes(true) {
+			if fr.Method == fiber.MethodGet {
+				gets[auditKey("", fr.Path)] = true
+			}
+		}
+		for _, fr := range app.GetRoutes(true) {
+			key := auditKey(fr.Method, fr.Path)
+			registered[key] = true
+			if documented[key] || len(fr.Handlers) == 0 || docs[handlerID(fr.Handlers[len(fr.Handlers)-1])] {
+				continue
+			}
+			// Fiber registers a HEAD route along with every GET route.
+			if fr.Method == fiber.MethodHead && gets[auditKey("", fr.Path)] {
+				continue
+			}
+			documented[key] = true // reported once
+			issues = append(issues, AuditIssue{
+				Kind:    AuditUndocumentedRoute,
+				Method:  fr.Method,
+				Path:    fr.Path,
+				Message: "route registered on Fiber is not documented",
+				Source:  handlerLocation(fr.Handlers),
+			})
+		}
+	}
+
+	for _, rt := range routes {
+		switch {
+		case rt.err != nil:
+			issues = append(issues, AuditIssue{
+				Kind:    AuditMissingHandler,
+				Method:  rt.method,
+				Path:    rt.path,
+				Message: strings.TrimPrefix(rt.err.Error(), rt.method+" "+rt.path+": "),
+				Source:  rt.source,
+			})
+			continue
+		case rt.unsupported != nil:
+			issues = append(issues, AuditIssue{
+				Kind:    AuditUnsupportedMethod,
+				Method:  rt.method,
+				Path:    rt.path,
+				Message: strings.TrimPrefix(rt.unsupported.Error(), rt.method+" "+rt.route.path+": "),
+				Source:  rt.source,
+			})
+		case app != nil && !registered[auditKey(rt.method, rt.path)]:
+			issues = append(issues, AuditIssue{
+				Kind:    AuditMissingHandler,
+				Method:  rt.method,
+				Path:    rt.path,
+				Message: "documented route is not registered on Fiber",
+				Source:  rt.source,
+			})
+		}
+		issues = append(issues, rt.auditPathParams(rt.path)...)
+	}
+	return issues
+}
+
+// auditPathParams reports the path parameters of the request structures of
+// the route that are not in its path.
+func (r *route) auditPathParams(path string) []AuditIssue {
+	cfg := r.config()
+	structures := make([]any, 0, len(cfg.Requests))
+	for _, req := range cfg.Requests {
+		structures = append(structures, req.Structure)
+	}
+	declared := util.PathParamNames(structures...)
+	if len(declared) == 0 {
+		return nil
+	}
+
+	params := util.ParsePath(path)[0].Params
+	inPath := make(map[string]bool, len(params))
+	names := make([]string, 0, len(params))
+	for _, p := range params {
+		inPath[p.Name] = true
+		names = append(names, p.Name)
+	}
+
+	var mismatched []string
+	for name := range declared {
+		if !inPath[name] {
+			mismatched = append(mismatched, name)
+		}
+	}
+	sort.Strings(mismatched)
+
+	var issues []AuditIssue
+	for _, name := range mismatched {
+		msg := fmt.Sprintf("path parameter %q of the request is not in the route path", name)
+		if len(names) > 0 {
+			msg += fmt.Sprintf(", which has %s", strings.Join(names, ", "))
+		}
+		issues = append(issues, AuditIssue{
+			Kind:    AuditPathParamMismatch,
+			Method:  r.method,
+			Path:    path,
+			Message: msg,
+			Source:  r.source,
+		})
+	}
+	return issues
+}
+
+// auditRoutes returns the routes of the registry and of the mounted
+// registries, with their paths prefixed with prefix, and the handlers of
+// their docs routes.
+func (r *registry) auditRoutes(prefix string) ([]auditRoute, map[uintptr]bool) {
+	r.mu.Lock()
+	var routes []auditRoute
+	var mounts []*mount
+	var walk func(g *group, inherited []option.GroupOption)
+	walk = func(g *group, inherited []option.GroupOption) {
+		inherited = append(append([]option.GroupOption{}, inherited...), g.opts...)
+		for _, rt := range g.routes {
+			routes = append(routes, auditRoute{route: rt, path: prefix + rt.path, unsupported: rt.unsupportedMethod(inherited)})
+		}
+		for _, child := range g.groups {
+			walk(child, inherited)
+		}
+		mounts = append(mounts, g.mounts...)
+	}
+	walk(r.root, nil)
+	docs := make(map[uintptr]bool, len(r.docsHandlers))
+	for id := range r.docsHandlers {
+		docs[id] = true
+	}
+	r.mu.Unlock()
+
+	for _, m := range mounts {
+		mounted, mountedDocs := m.reg.auditRoutes(prefix + m.prefix)
+		routes = append(routes, mounted...)
+		for id := range mountedDocs {
+			docs[id] = true
+		}
+	}
+	return routes, docs
+}
+
+// auditKey identifies a route by method and path, ignoring the differences
+// of path syntax between the router and Fiber, such as repeated slashes.
+func auditKey(method, path string) string {
+	for strings.Contains(path, "//") {
+		path = strings.ReplaceAll(path, "//", "/")
+	}
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return method + " " + path
+}
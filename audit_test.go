@@ -0,0 +1,86 @@
+package fiberopenapi_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi"
+	"github.com/oaswrap/spec/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func HealthHandler(c *fiber.Ctx) error {
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func TestRouter_Audit(t *testing.T) {
+	type GetUserRequest struct {
+		ID int `path:"id"`
+	}
+	type GetPostRequest struct {
+		UserID int `path:"userId"`
+		PostID int `path:"postId"`
+	}
+
+	t.Run("consistent routes", func(t *testing.T) {
+		app := fiber.New()
+		r := fiberopenapi.NewRouter(app)
+		api := r.Group("/api")
+		api.Get("/users/:id", PingHandler).With(option.Request(new(GetUserRequest)))
+		api.Get("/users/:userId/posts/:postId?", PingHandler).With(option.Request(new(GetPostRequest)))
+		api.Connect("/tunnel", PingHandler).With(option.Hide())
+
+		sub := fiberopenapi.NewRouter(fiber.New())
+		sub.Get("/orders", PingHandler)
+		r.Mount("/shop", sub)
+
+		assert.Empty(t, r.Audit())
+	})
+
+	t.Run("inconsistent routes", func(t *testing.T) {
+		app := fiber.New()
+		r := fiberopenapi.NewRouter(app)
+		r.Get("/users/:userId", PingHandler).With(option.Request(new(GetUserRequest)))
+		app.Post("/health", HealthHandler)
+		r.Connect("/tunnel", PingHandler)
+
+		issues := r.Audit()
+		require.Len(t, issues, 3)
+
+		assert.Equal(t, fiberopenapi.AuditUndocumentedRoute, issues[0].Kind)
+		assert.Equal(t, "POST", issues[0].Method)
+		assert.Equal(t, "/health", issues[0].Path)
+		assert.Equal(t, "audit_test.go", filepath.Base(issues[0].Source.File), "expected the location of the handler")
+
+		assert.Equal(t, fiberopenapi.AuditPathParamMismatch, issues[1].Kind)
+		assert.Equal(t, "GET", issues[1].Method)
+		assert.Equal(t, "/users/:userId", issues[1].Path)
+		assert.Equal(t, `path parameter "id" of the request is not in the route path, which has userId`, issues[1].Message)
+		assert.Equal(t, "audit_test.go", filepath.Base(issues[1].Source.File), "expected the location of the registration")
+		assert.Contains(t, issues[1].String(), "audit_test.go:")
+
+		assert.Equal(t, fiberopenapi.AuditUnsupportedMethod, issues[2].Kind)
+		assert.Equal(t, "CONNECT", issues[2].Method)
+		assert.Equal(t, "/tunnel", issues[2].Path)
+		assert.Equal(t, "OpenAPI does not support the CONNECT method, hide the route with option.Hide()", issues[2].Message)
+	})
+
+	t.Run("app routes", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/ping", PingHandler)
+
+		g := fiberopenapi.FromApp(app, fiberopenapi.Config{})
+		g.Operation("GET", "/ping").With(option.Summary("Ping"))
+		g.Operation("DELETE", "/ping").With(option.Summary("Delete ping"))
+
+		issues := g.Audit()
+		require.Len(t, issues, 1)
+		assert.Equal(t, fiberopenapi.AuditMissingHandler, issues[0].Kind)
+		assert.Equal(t, "DELETE", issues[0].Method)
+		assert.Equal(t, "/ping", issues[0].Path)
+		assert.Equal(t, "no route registered on the app", issues[0].Message)
+		assert.Equal(t, "audit_test.go", filepath.Base(issues[0].Source.File))
+	})
+}
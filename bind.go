@@ -0,0 +1,105 @@
+package fiberopenapi
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/oaswrap/fiberopenapi/internal/binding"
+	"github.com/oaswrap/fiberopenapi/internal/util"
+)
+
+// RequestError is the error of a request that can not be bound to one of the
+// request structures of its route, passed to Config.RequestErrorHandler.
+//
+// Its Status is 400 for malformed input, 415 for an unsupported body and
+// 422 for input that does not match the schema of the request structure.
+type RequestError = binding.Error
+
+// FieldError describes an invalid field of a RequestError.
+type FieldError = binding.FieldError
+
+// ProblemDetails is the RFC 9457 problem response sent by DefaultRequestErrorHandler.
+type ProblemDetails struct {
+	Type   string       `json:"type"`
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Detail string       `json:"detail,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// DefaultRequestErrorHandler answers a RequestError with an
+// application/problem+json response, other errors are returned as is.
+func DefaultRequestErrorHandler(c *fiber.Ctx, err error) error {
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		return err
+	}
+	return c.Status(reqErr.Status).JSON(ProblemDetails{
+		Type:   "about:blank",
+		Title:  utils.StatusMessage(reqErr.Status),
+		Status: reqErr.Status,
+		Detail: reqErr.Detail,
+		Errors: reqErr.Errors,
+	}, "application/problem+json")
+}
+
+// Request returns the request structure of type T bound for the current request,
+// or nil if the route does not declare it with option.Request or request binding
+// is disabled.
+func Request[T any](c *fiber.Ctx) *T {
+	bound, ok := c.Locals(requestsKey{}).(map[reflect.Type]any)
+	if !ok {
+		return nil
+	}
+	v, _ := bound[reflect.TypeOf((*T)(nil)).Elem()].(*T)
+	return v
+}
+
+// requestsKey is the key of the bound request structures in the context locals.
+type requestsKey struct{}
+
+// requestBinder binds the request structures of the routes registered with
+// Config.BindRequests enabled, and of the typed handlers.
+type requestBinder struct {
+	binder       *binding.Binder
+	errorHandler fiber.ErrorHandler
+	enabled      bool
+}
+
+// routeBinding holds the request binding state of a route.
+type routeBinding struct {
+	*requestBinder
+	pathParams map[string]string
+}
+
+func newRouteBinding(b *requestBinder, path string) *routeBinding {
+	pathParams := make(map[string]string)
+	for _, p := range util.ParsePath(path)[0].Params {
+		pathParams[p.Name] = p.FiberName
+	}
+	return &routeBinding{requestBinder: b, pathParams: pathParams}
+}
+
+// bind is the handler binding the request structures before the route handlers.
+func (r *route) bind(c *fiber.Ctx) error {
+	contentType := c.Get(fiber.HeaderContentType)
+
+	bound := make(map[reflect.Type]any)
+	for _, req := range r.config().Requests {
+		// Structures declared for another content type describe another body.
+		if req.ContentType != "" && contentType != "" && !strings.HasPrefix(contentType, req.ContentType) {
+			continue
+		}
+		v, err := r.binding.binder.Bind(c, req.Structure, r.binding.pathParams)
+		if err != nil {
+			return r.binding.errorHandler(c, err)
+		}
+		bound[reflect.TypeOf(v).Elem()] = v
+	}
+	c.Locals(requestsKey{}, bound)
+
+	return c.Next()
+}
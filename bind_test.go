@@ -0,0 +1,256 @@
+package fiberopenapi_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi"
+	"github.com/oaswrap/spec/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Address struct {
+	City string `json:"city" required:"true" minLength:"2"`
+}
+
+type UpdatePetRequest struct {
+	ID      int      `path:"id" minimum:"1"`
+	Notify  bool     `query:"notify"`
+	Fields  []string `query:"fields" enum:"name,status"`
+	TraceID string   `header:"X-Trace-Id" pattern:"^[a-f0-9]+$"`
+	Session string   `cookie:"session"`
+
+	Name    string   `json:"name" required:"true" maxLength:"10"`
+	Status  string   `json:"status" enum:"available,sold"`
+	Age     int      `json:"age" minimum:"0" maximum:"30"`
+	Address *Address `json:"address"`
+}
+
+type SearchRequest struct {
+	Query string `query:"q" required:"true"`
+	Limit int    `query:"limit" minimum:"1" maximum:"100"`
+}
+
+type FilterRequest struct {
+	Tags   []string `query:"tags"`
+	IDs    []int    `query:"ids" collectionFormat:"csv"`
+	Labels []string `header:"X-Labels"`
+}
+
+type UploadRequest struct {
+	Title string `formData:"title" required:"true"`
+}
+
+func TestRouter_BindRequests(t *testing.T) {
+	app := fiber.New()
+	r := fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{BindRequests: true})
+
+	r.Put("/pets/:id<int>", func(c *fiber.Ctx) error {
+		return c.JSON(fiberopenapi.Request[UpdatePetRequest](c))
+	}).With(option.Request(new(UpdatePetRequest)))
+	r.Get("/search", func(c *fiber.Ctx) error {
+		return c.JSON(fiberopenapi.Request[SearchRequest](c))
+	}).With(option.Request(new(SearchRequest)))
+	r.Get("/filter", func(c *fiber.Ctx) error {
+		return c.JSON(fiberopenapi.Request[FilterRequest](c))
+	}).With(option.Request(new(FilterRequest)))
+	r.Post("/uploads", func(c *fiber.Ctx) error {
+		return c.JSON(fiberopenapi.Request[UploadRequest](c))
+	}).With(option.Request(new(UploadRequest)))
+	r.Get("/ping", func(c *fiber.Ctx) error {
+		assert.Nil(t, fiberopenapi.Request[SearchRequest](c), "expected no bound request without option.Request")
+		return c.SendString("pong")
+	})
+
+	do := func(t *testing.T, req *http.Request) (*http.Response, []byte) {
+		t.Helper()
+		res, err := app.Test(req, -1)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, body
+	}
+
+	t.Run("binds every location", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPut, "/pets/7?notify=true&fields=name&fields=status",
+			strings.NewReader(`{"name":"Rex","status":"sold","age":3,"address":{"city":"Oslo"}}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set("X-Trace-Id", "abc123")
+		req.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+
+		res, body := do(t, req)
+		require.Equal(t, http.StatusOK, res.StatusCode, string(body))
+
+		var got UpdatePetRequest
+		require.NoError(t, json.Unmarshal(body, &got))
+		assert.Equal(t, UpdatePetRequest{
+			ID:      7,
+			Notify:  true,
+			Fields:  []string{"name", "status"},
+			TraceID: "abc123",
+			Session: "s1",
+			Name:    "Rex",
+			Status:  "sold",
+			Age:     3,
+			Address: &Address{City: "Oslo"},
+		}, got)
+	})
+
+	t.Run("binds query parameters", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/search?q=cat&limit=5", nil)
+		res, body := do(t, req)
+		require.Equal(t, http.StatusOK, res.StatusCode, string(body))
+		assert.JSONEq(t, `{"Query":"cat","Limit":5}`, string(body))
+	})
+
+	t.Run("binds arrays in their documented style", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/filter?tags=a,b&tags=c&ids=1,2&ids=3", nil)
+		req.Header.Set("X-Labels", "x,y")
+		res, body := do(t, req)
+		require.Equal(t, http.StatusOK, res.StatusCode, string(body))
+		assert.JSONEq(t, `{"Tags":["a,b","c"],"IDs":[1,2,3],"Labels":["x","y"]}`, string(body))
+
+		req, _ = http.NewRequest(http.MethodGet, "/filter?tags=a,b", nil)
+		res, body = do(t, req)
+		require.Equal(t, http.StatusOK, res.StatusCode, string(body))
+		assert.JSONEq(t, `{"Tags":["a,b"],"IDs":null,"Labels":null}`, string(body), "expected exploded arrays not to be split")
+
+		schema, err := r.MarshalYAML()
+		require.NoError(t, err)
+		assert.Contains(t, string(schema), "explode: false\n        in: query\n        name: ids")
+	})
+
+	t.Run("binds form data", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/uploads", strings.NewReader("title=hello"))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationForm)
+		res, body := do(t, req)
+		require.Equal(t, http.StatusOK, res.StatusCode, string(body))
+		assert.JSONEq(t, `{"Title":"hello"}`, string(body))
+	})
+
+	t.Run("routes without requests", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/ping", nil)
+		res, _ := do(t, req)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+		errors []fiberopenapi.FieldError
+	}{
+		{
+			name:   "malformed parameter",
+			method: http.MethodGet,
+			target: "/search?q=cat&limit=many",
+			status: http.StatusBadRequest,
+			errors: []fiberopenapi.FieldError{{In: "query", Field: "limit", Message: "must be an integer"}},
+		},
+		{
+			name:   "missing required parameter",
+			method: http.MethodGet,
+			target: "/search",
+			status: http.StatusUnprocessableEntity,
+			errors: []fiberopenapi.FieldError{{In: "query", Field: "q", Message: "is required"}},
+		},
+		{
+			name:   "parameter out of range",
+			method: http.MethodGet,
+			target: "/search?q=cat&limit=500",
+			status: http.StatusUnprocessableEntity,
+			errors: []fiberopenapi.FieldError{{In: "query", Field: "limit", Message: "must be less than or equal to 100"}},
+		},
+		{
+			name:   "malformed body",
+			method: http.MethodPut,
+			target: "/pets/1",
+			body:   `{"name":`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid body",
+			method: http.MethodPut,
+			target: "/pets/1?fields=owner",
+			body:   `{"name":"A very long name","status":"lost","age":31,"address":{}}`,
+			status: http.StatusUnprocessableEntity,
+			errors: []fiberopenapi.FieldError{
+				{In: "query", Field: "fields[0]", Message: `must be one of ["name", "status"]`},
+				{In: "body", Field: "address.city", Message: "is required"},
+				{In: "body", Field: "age", Message: "must be less than or equal to 30"},
+				{In: "body", Field: "name", Message: "must be at most 10 characters long"},
+				{In: "body", Field: "status", Message: `must be one of ["available", "sold"]`},
+			},
+		},
+		{
+			name:   "missing body",
+			method: http.MethodPut,
+			target: "/pets/1",
+			status: http.StatusUnprocessableEntity,
+			errors: []fiberopenapi.FieldError{{In: "body", Field: "name", Message: "is required"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			}
+			res, body := do(t, req)
+			require.Equal(t, tt.status, res.StatusCode, string(body))
+			assert.Equal(t, "application/problem+json", res.Header.Get(fiber.HeaderContentType))
+
+			var problem fiberopenapi.ProblemDetails
+			require.NoError(t, json.Unmarshal(body, &problem))
+			assert.Equal(t, tt.status, problem.Status)
+			assert.Equal(t, tt.errors, problem.Errors)
+		})
+	}
+}
+
+func TestRouter_BindRequests_ErrorHandler(t *testing.T) {
+	app := fiber.New()
+	r := fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{
+		BindRequests: true,
+		RequestErrorHandler: func(c *fiber.Ctx, err error) error {
+			var reqErr *fiberopenapi.RequestError
+			require.True(t, errors.As(err, &reqErr))
+			return c.Status(http.StatusBadRequest).SendString(reqErr.Error())
+		},
+	})
+	r.Get("/search", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	}).With(option.Request(new(SearchRequest)))
+
+	req, _ := http.NewRequest(http.MethodGet, "/search", nil)
+	res, err := app.Test(req, -1)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	assert.Equal(t, "request does not match the schema: query q is required", string(body))
+}
+
+func TestRouter_BindRequestsDisabled(t *testing.T) {
+	app := fiber.New()
+	r := fiberopenapi.NewRouter(app)
+	r.Get("/search", func(c *fiber.Ctx) error {
+		assert.Nil(t, fiberopenapi.Request[SearchRequest](c))
+		return c.SendString("ok")
+	}).With(option.Request(new(SearchRequest)))
+
+	req, _ := http.NewRequest(http.MethodGet, "/search?limit=many", nil)
+	res, err := app.Test(req, -1)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
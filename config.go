@@ -0,0 +1,141 @@
+package fiberopenapi
+
+import "github.com/gofiber/fiber/v2"
+
+// Config defines the Fiber specific configuration of the OpenAPI router.
+//
+// It complements the OpenAPI options from the spec package, which describe the
+// document itself, with settings for how the router serves it.
+type Config struct {
+	// SpecFormat is the format of the specification loaded by the docs UI,
+	// either "yaml" or "json". Defaults to "yaml".
+	//
+	// The specification is always served in both formats, at
+	// "<docs>/openapi.yaml" and "<docs>/openapi.json", and at "<docs>/openapi"
+	// in the format requested by the Accept header, falling back to SpecFormat
+	// when the header names neither format, such as the Accept header of a
+	// browser, which only accepts them through "*/*".
+	SpecFormat string
+
+	// SpecCacheControl is the Cache-Control header of the specification.
+	// Defaults to "no-cache", clients revalidate it on every use.
+	//
+	// The specification is served with a strong ETag and a Last-Modified
+	// date, and conditional requests are answered with 304 Not Modified.
+	SpecCacheControl string
+
+	// CompressSpec compresses the specification with gzip, brotli or deflate
+	// when the client accepts one of them, using the Fiber compress middleware.
+	CompressSpec bool
+
+	// DocsUI is the documentation UI served at the docs path, one of
+	// SwaggerUI, SwaggerUIEmbedded, Redoc, Scalar, RapiDoc and Elements,
+	// or a custom DocsUI.
+	// Defaults to SwaggerUI.
+	DocsUI DocsUI
+
+	// DocsCSPNonce returns the Content-Security-Policy nonce of a request,
+	// typically set by the middleware writing the policy. When it returns a
+	// nonce, the nonce is added to the scripts and styles of the docs page.
+	DocsCSPNonce func(c *fiber.Ctx) string
+
+	// DocsMiddleware runs before the handlers of the docs and specification
+	// routes, and only of those routes, for example to require basic auth,
+	// an API key or an allowed IP address to read the documentation.
+	DocsMiddleware []fiber.Handler
+
+	// DocsRouter is the router the docs and specification routes are
+	// registered on, for example an app listening on an admin port.
+	// Defaults to the router the API routes are registered on.
+	DocsRouter fiber.Router
+
+	// NameOperationIDs makes the name set with Route.Name the operationId of
+	// the route, unless option.OperationID sets one.
+	NameOperationIDs bool
+
+	// OperationID returns the operationId of the operations that have none,
+	// for example DefaultOperationID. Operations have no operationId when nil.
+	//
+	// Validate reports the operationIds shared by several operations.
+	OperationID OperationIDFunc
+
+	// SourceExtension adds an "x-source" extension to every operation with
+	// the file and line of its registration, for internal tooling. The file
+	// is relative to the working directory when it is inside it.
+	SourceExtension bool
+
+	// SecurityMiddleware maps middleware to the security requirements they
+	// enforce, so that routes behind one of them are not documented as public.
+	// Groups created with one of the middleware are documented as with
+	// option.GroupSecurity, and routes registered with it, or after Use added
+	// it to their router or a parent router, as with option.Security.
+	// Use with a prefix is not taken into account. Validate reports the
+	// middleware that are not found in the handlers of any route or group.
+	SecurityMiddleware []SecurityMiddleware
+
+	// BindRequests enables request binding. Before the handlers of a route
+	// run, the router binds the request structures declared with
+	// option.Request from the path, query, header, cookie and formData
+	// parameters and the JSON body, using the tags read by the spec
+	// reflector, and validates them against their JSON schema.
+	//
+	// Handlers get the bound structures with Request. Requests that can not
+	// be bound are answered by RequestErrorHandler.
+	BindRequests bool
+
+	// RequestErrorHandler handles the requests rejected by request binding,
+	// err is a *RequestError unless the request structure itself is invalid.
+	// Defaults to DefaultRequestErrorHandler.
+	RequestErrorHandler fiber.ErrorHandler
+
+	// ValidateResponses enables response validation, meant for development
+	// and tests. After the handlers of a route run, the router checks the
+	// response against the responses declared with option.Response: its
+	// status must be declared and its JSON body must match the schema of the
+	// declared structure. Routes without declared responses are not checked.
+	ValidateResponses bool
+
+	// ResponseValidationHandler handles the responses that fail validation.
+	// Defaults to LogInvalidResponse, use FailInvalidResponse to fail them.
+	ResponseValidationHandler ResponseValidationHandler
+
+	// MockResponses replaces the handlers of the routes with handlers
+	// answering with an example of their documented responses, so that the
+	// API runs on a plain Fiber app without its backend, for example for the
+	// development of its clients. Middleware added with Use still runs, and
+	// routes may be registered without handlers.
+	//
+	// The response is the first documented success response, or the one
+	// requested with a "Prefer: code=404" header. Its body is the example of
+	// the response schema, set with example tags, or a value derived from
+	// the schema. See MockHandler to mock only some routes.
+	MockResponses bool
+
+	// InjectExamples adds an example to every request and response body of
+	// the specification that has none, built from its schema like the
+	// examples of Examples, so that the docs UI shows complete payloads.
+	InjectExamples bool
+
+	// SpecView returns the view of the specification served to a request,
+	// for example from its tenant, the role of its user or a header. It is
+	// evaluated on every request of the specification, so that the view can
+	// follow runtime feature flags. The operations with one of the tags
+	// hidden by the view are removed from the specification, so that public
+	// partners only see the public operations while internal users get the
+	// full specification from the same router:
+	//
+	//	SpecView: func(c *fiber.Ctx) fiberopenapi.SpecView {
+	//		if c.Get("X-Audience") == "internal" {
+	//			return fiberopenapi.SpecView{}
+	//		}
+	//		return fiberopenapi.SpecView{HideTags: []string{"internal"}}
+	//	},
+	//
+	// The specification of each view is cached like the full specification.
+	// It is served with a private Cache-Control header, SpecCacheControl
+	// without its directives for shared caches, so that a shared cache does
+	// not serve the full specification to other clients. Restrict the access
+	// to the full specification with DocsMiddleware or the view itself,
+	// Validate and the generator methods are not affected.
+	SpecView func(c *fiber.Ctx) SpecView
+}
@@ -0,0 +1,43 @@
+package fiberopenapi
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/oaswrap/fiberopenapi/internal/specdiff"
+)
+
+// SpecDiff is the difference between a baseline specification and the
+// specification of a generator, see CompareSpec.
+type SpecDiff = specdiff.Diff
+
+// SpecChange is a change of an operation reported by a SpecDiff.
+type SpecChange = specdiff.Change
+
+// CompareSpec compares the specification of gen with the baseline
+// specification file, in JSON or YAML, for example a committed openapi.yaml.
+//
+// It reports the added, removed and changed operations. Removed operations,
+// new required parameters and properties, narrowed request enums, removed
+// response properties and changed types are breaking changes.
+//
+// See fiberopenapitest.AssertNoBreakingChanges to check it in a test.
+func CompareSpec(gen Generator, baselinePath string) (*SpecDiff, error) {
+	data, err := os.ReadFile(baselinePath)
+	if err != nil {
+		return nil, err
+	}
+	baseline, err := specdiff.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", baselinePath, err)
+	}
+	schema, err := gen.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	current, err := specdiff.Parse(schema)
+	if err != nil {
+		return nil, err
+	}
+	return specdiff.Compare(baseline, current), nil
+}
@@ -0,0 +1,59 @@
+package fiberopenapi_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi"
+	"github.com/oaswrap/spec/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareSpec(t *testing.T) {
+	type ListPetsRequest struct {
+		Limit int `query:"limit"`
+	}
+	type ListPetsRequestV2 struct {
+		Limit  int    `query:"limit"`
+		Status string `query:"status" required:"true"`
+	}
+	newRouter := func(version string, breaking bool) fiberopenapi.Generator {
+		r := fiberopenapi.NewRouter(fiber.New(), option.WithVersion(version))
+		if breaking {
+			r.Get("/pets", PingHandler).With(option.Request(new(ListPetsRequestV2)))
+		} else {
+			r.Get("/pets", PingHandler).With(option.Request(new(ListPetsRequest)))
+			r.Delete("/pets/:id", PingHandler)
+		}
+		r.Get("/stores", PingHandler)
+		return r
+	}
+
+	baseline := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, newRouter("1.0.0", false).WriteSchemaTo(baseline))
+
+	t.Run("unchanged", func(t *testing.T) {
+		diff, err := fiberopenapi.CompareSpec(newRouter("1.0.0", false), baseline)
+		require.NoError(t, err)
+		assert.False(t, diff.HasChanges())
+	})
+	t.Run("breaking changes", func(t *testing.T) {
+		diff, err := fiberopenapi.CompareSpec(newRouter("1.1.0", true), baseline)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"DELETE /pets/{id}"}, diff.Removed)
+		assert.Equal(t, []string{"GET /pets"}, diff.Changed)
+		assert.Len(t, diff.Breaking(), 2)
+	})
+	t.Run("breaking changes with a version bump", func(t *testing.T) {
+		diff, err := fiberopenapi.CompareSpec(newRouter("2.0.0", true), baseline)
+		require.NoError(t, err)
+		assert.Len(t, diff.Breaking(), 2)
+		assert.True(t, diff.VersionBumped())
+	})
+	t.Run("missing baseline", func(t *testing.T) {
+		_, err := fiberopenapi.CompareSpec(newRouter("1.0.0", false), filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.Error(t, err)
+	})
+}
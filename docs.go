@@ -0,0 +1,33 @@
+package fiberopenapi
+
+import "github.com/oaswrap/fiberopenapi/internal/handler"
+
+// DocsUI renders the documentation UI served at the docs path.
+//
+// Custom UIs implement Handler, returning the Fiber handler serving the UI
+// for the given settings.
+type DocsUI = handler.UI
+
+// DocsUIConfig holds the settings passed to a DocsUI: the page title, the URL
+// of the specification, the docs path and the Swagger UI settings.
+type DocsUIConfig = handler.UIConfig
+
+// SwaggerUI returns the Swagger UI documentation UI, the default one.
+func SwaggerUI() DocsUI { return handler.SwaggerUI{} }
+
+// SwaggerUIEmbedded returns the Swagger UI documentation UI with its assets
+// embedded in the binary and served under the docs path, for environments
+// without access to a CDN or with a strict Content-Security-Policy.
+func SwaggerUIEmbedded() DocsUI { return handler.SwaggerUIEmbedded{} }
+
+// Redoc returns the Redoc documentation UI.
+func Redoc() DocsUI { return handler.Redoc{} }
+
+// Scalar returns the Scalar API reference documentation UI.
+func Scalar() DocsUI { return handler.Scalar{} }
+
+// RapiDoc returns the RapiDoc documentation UI.
+func RapiDoc() DocsUI { return handler.RapiDoc{} }
+
+// Elements returns the Stoplight Elements documentation UI.
+func Elements() DocsUI { return handler.Elements{} }
@@ -0,0 +1,229 @@
+package fiberopenapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi/internal/jsonref"
+	"github.com/oaswrap/fiberopenapi/internal/sample"
+	"github.com/oaswrap/fiberopenapi/internal/util"
+)
+
+// BodyExample is an example body of a request or a response.
+type BodyExample struct {
+	ContentType string
+	Value       any
+}
+
+// OperationExamples are the example bodies of an operation, see Examples.
+type OperationExamples struct {
+	Method      string
+	Path        string // OpenAPI path template
+	OperationID string
+
+	// Request is the example request body, nil if the operation has none.
+	Request *BodyExample
+	// Responses are the example response bodies by status, such as "200".
+	// Responses without a body are not listed.
+	Responses map[string]BodyExample
+}
+
+// Name returns the operationId of the operation, or DefaultOperationID if it has none.
+func (o OperationExamples) Name() string {
+	if o.OperationID != "" {
+		return o.OperationID
+	}
+	return DefaultOperationID(o.Method, o.Path)
+}
+
+// Examples returns the example bodies of the requests and responses of the
+// operations documented by gen, sorted by path and method.
+//
+// The example of a body is the example set on its media type, or a value
+// built from its schema: the example, default or first enum value of each
+// schema, set with the example, default and enum tags, or else a value
+// derived from its type and format. Objects have a value for every property
+// and arrays one item. JSON content types are preferred.
+func Examples(gen Generator) ([]OperationExamples, error) {
+	schema, err := gen.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		return nil, err
+	}
+
+	var examples []OperationExamples
+	paths, _ := doc["paths"].(map[string]any)
+	for _, path := range sortedKeys(paths) {
+		item, _ := paths[path].(map[string]any)
+		for _, method := range util.OperationMethods {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			ex := OperationExamples{
+				Method:    strings.ToUpper(method),
+				Path:      path,
+				Responses: make(map[string]BodyExample),
+			}
+			ex.OperationID, _ = op["operationId"].(string)
+			if body, ok := bodyExample(doc, op["requestBody"]); ok {
+				ex.Request = &body
+			}
+			responses, _ := op["responses"].(map[string]any)
+			for status, resp := range responses {
+				if body, ok := bodyExample(doc, resp); ok {
+					ex.Responses[status] = body
+				}
+			}
+			examples = append(examples, ex)
+		}
+	}
+	return examples, nil
+}
+
+// WriteExamples writes the example bodies of the operations documented by
+// gen to dir, one directory per operation named after Name, with the request
+// body in "request.json" and the response bodies in files named after their
+// status, such as "200.json". Bodies that are text are written in ".txt" files.
+//
+// An error is returned, before anything is written, when two operations have
+// the same name, such as an operationId equal to the default operationId of
+// another operation.
+func WriteExamples(gen Generator, dir string) error {
+	examples, err := Examples(gen)
+	if err != nil {
+		return err
+	}
+	names := make(map[string]OperationExamples, len(examples))
+	for _, ex := range examples {
+		if ex.Request == nil && len(ex.Responses) == 0 {
+			continue
+		}
+		if other, dup := names[ex.Name()]; dup {
+			return fmt.Errorf("operations %s %s and %s %s have the same example name %q",
+				other.Method, other.Path, ex.Method, ex.Path, ex.Name())
+		}
+		names[ex.Name()] = ex
+	}
+	write := func(path string, body BodyExample) error {
+		data, err := json.MarshalIndent(body.Value, "", "  ")
+		ext := ".json"
+		if s, ok := body.Value.(string); ok && !strings.HasSuffix(body.ContentType, "json") {
+			data, err, ext = []byte(s), nil, ".txt"
+		}
+		if err != nil {
+			return fmt.Errorf("failed to marshal example %s: %w", path, err)
+		}
+		return os.WriteFile(path+ext, data, 0644)
+	}
+	for _, ex := range examples {
+		if ex.Request == nil && len(ex.Responses) == 0 {
+			continue
+		}
+		opDir := filepath.Join(dir, ex.Name())
+		if err := os.MkdirAll(opDir, 0755); err != nil {
+			return err
+		}
+		if ex.Request != nil {
+			if err := write(filepath.Join(opDir, "request"), *ex.Request); err != nil {
+				return err
+			}
+		}
+		for status, body := range ex.Responses {
+			if err := write(filepath.Join(opDir, status), body); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bodyExample returns the example of a request body or a response.
+func bodyExample(doc map[string]any, v any) (BodyExample, bool) {
+	body, _ := v.(map[string]any)
+	if ref, _ := body["$ref"].(string); ref != "" {
+		body = jsonref.Resolve(doc, ref)
+	}
+	content, _ := body["content"].(map[string]any)
+	contentType := exampleContentType(content)
+	if contentType == "" {
+		return BodyExample{}, false
+	}
+	media, _ := content[contentType].(map[string]any)
+	return BodyExample{ContentType: contentType, Value: mediaExample(doc, media)}, true
+}
+
+// mediaExample returns the example of a media type, or a value built from its schema.
+func mediaExample(doc map[string]any, media map[string]any) any {
+	if example, ok := media["example"]; ok {
+		return example
+	}
+	if examples, ok := media["examples"].(map[string]any); ok && len(examples) > 0 {
+		example, _ := examples[sortedKeys(examples)[0]].(map[string]any)
+		if ref, _ := example["$ref"].(string); ref != "" {
+			example = jsonref.Resolve(doc, ref)
+		}
+		if value, ok := example["value"]; ok {
+			return value
+		}
+	}
+	return sample.Value(doc, media["schema"])
+}
+
+// exampleContentType returns the content type of the example of a body,
+// JSON if the body has a JSON content type.
+func exampleContentType(content map[string]any) string {
+	if _, ok := content[fiber.MIMEApplicationJSON]; ok {
+		return fiber.MIMEApplicationJSON
+	}
+	types := sortedKeys(content)
+	for _, contentType := range types {
+		if strings.HasSuffix(contentType, "json") {
+			return contentType
+		}
+	}
+	if len(types) > 0 {
+		return types[0]
+	}
+	return ""
+}
+
+// addExamples adds an example to the request and response bodies of the
+// operations of doc that have none, see Config.InjectExamples.
+func addExamples(doc map[string]any) {
+	paths, _ := doc["paths"].(map[string]any)
+	for _, item := range paths {
+		item, _ := item.(map[string]any)
+		for method, op := range item {
+			op, ok := op.(map[string]any)
+			if !ok || !util.IsOperationMethod(method) {
+				continue
+			}
+			bodies := []any{op["requestBody"]}
+			responses, _ := op["responses"].(map[string]any)
+			for _, status := range sortedKeys(responses) {
+				bodies = append(bodies, responses[status])
+			}
+			for _, body := range bodies {
+				body, _ := body.(map[string]any)
+				content, _ := body["content"].(map[string]any)
+				for _, media := range content {
+					media, ok := media.(map[string]any)
+					if !ok || media["example"] != nil || media["examples"] != nil {
+						continue
+					}
+					media["examples"] = map[string]any{
+						"default": map[string]any{"value": sample.Value(doc, media["schema"])},
+					}
+				}
+			}
+		}
+	}
+}
@@ -27,6 +27,9 @@ func main() {
 		option.Response(200, new(HelloResponse)),
 	)
 
+	// Export the schema and exit when FIBEROPENAPI_EXPORT is set, e.g. in CI
+	fiberopenapi.ExportIfRequested(r)
+
 	// Write schema to file (optional)
 	if err := r.WriteSchemaTo("openapi.yaml"); err != nil {
 		log.Fatalf("Failed to write OpenAPI schema: %v", err)
@@ -52,12 +52,9 @@ func main() {
 			option.Request(new(model.FindPetsByTagsRequest)),
 			option.Response(200, new([]model.Pet)),
 		)
-		r.Get("/:petId", handler.GetPetByID).With(
+		r.Get("/:petId<int>", handler.GetPetByID).With(
 			option.Summary("Get pet by ID"),
 			option.Description("Returns a single pet by its ID"),
-			option.Request(new(struct {
-				ID int64 `path:"petId"`
-			})),
 			option.Response(200, new(model.Pet)),
 		)
 		r.Post("/:petId", handler.UpdatePetFormData).With(
@@ -66,16 +63,16 @@ func main() {
 			option.Request(new(model.UpdatePetFormData)),
 			option.Response(200, new(model.Pet)),
 		)
-		r.Delete("/:petId", handler.DeletePet).With(
+		r.Delete("/:petId<int>", handler.DeletePet).With(
 			option.Summary("Delete a pet"),
 			option.Description("Deletes a pet from the store"),
-			option.Request(new(struct {
-				ID int64 `path:"petId"`
-			})),
 			option.Response(204, nil),
 		)
 	}).With(option.GroupTags("Pets"))
 
+	// Export the schema and exit when FIBEROPENAPI_EXPORT is set, e.g. in CI
+	fiberopenapi.ExportIfRequested(r)
+
 	// Validate the OpenAPI configuration
 	if err := r.Validate(); err != nil {
 		log.Fatalf("OpenAPI validation failed: %v", err)
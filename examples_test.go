@@ -0,0 +1,116 @@
+package fiberopenapi_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi"
+	"github.com/oaswrap/spec/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ExampleAddress struct {
+	Street string `json:"street" example:"1 Main Street"`
+	Email  string `json:"email" format:"email"`
+}
+
+type CreateOrderRequest struct {
+	Customer  string           `json:"customer" example:"Jane"`
+	Quantity  int              `json:"quantity" minimum:"1"`
+	Priority  string           `json:"priority" enum:"low,high"`
+	Addresses []ExampleAddress `json:"addresses"`
+	Due       time.Time        `json:"due"`
+}
+
+type Order struct {
+	ID       string         `json:"id" format:"uuid"`
+	Total    float64        `json:"total" example:"9.99"`
+	Shipping ExampleAddress `json:"shipping"`
+}
+
+func newExamplesRouter(config fiberopenapi.Config) fiberopenapi.Generator {
+	r := fiberopenapi.NewRouterWithConfig(fiber.New(), config)
+	r.Post("/orders", PingHandler).With(
+		option.OperationID("createOrder"),
+		option.Request(new(CreateOrderRequest)),
+		option.Response(201, new(Order)),
+		option.Response(400, new(ErrorResponse)),
+	)
+	r.Delete("/orders/:id", PingHandler)
+	return r
+}
+
+func TestExamples(t *testing.T) {
+	examples, err := fiberopenapi.Examples(newExamplesRouter(fiberopenapi.Config{}))
+	require.NoError(t, err)
+	require.Len(t, examples, 2)
+
+	create := examples[0]
+	assert.Equal(t, "POST", create.Method)
+	assert.Equal(t, "/orders", create.Path)
+	assert.Equal(t, "createOrder", create.Name())
+	require.NotNil(t, create.Request)
+	assert.Equal(t, "application/json", create.Request.ContentType)
+	assert.Equal(t, map[string]any{
+		"customer": "Jane",
+		"quantity": int64(1),
+		"priority": "low",
+		"addresses": []any{
+			map[string]any{"street": "1 Main Street", "email": "user@example.com"},
+		},
+		"due": "2024-01-02T15:04:05Z",
+	}, create.Request.Value)
+	assert.Equal(t, map[string]any{
+		"id":       "123e4567-e89b-12d3-a456-426614174000",
+		"total":    9.99,
+		"shipping": map[string]any{"street": "1 Main Street", "email": "user@example.com"},
+	}, create.Responses["201"].Value)
+	assert.Contains(t, create.Responses, "400")
+
+	remove := examples[1]
+	assert.Equal(t, "deleteOrdersById", remove.Name())
+	assert.Nil(t, remove.Request)
+	assert.Empty(t, remove.Responses)
+}
+
+func TestRouter_InjectExamples(t *testing.T) {
+	r := newExamplesRouter(fiberopenapi.Config{InjectExamples: true})
+	require.NoError(t, r.Validate())
+	schema, err := r.MarshalYAML()
+	require.NoError(t, err)
+	assert.Contains(t, string(schema), "examples:\n              default:\n                value:\n                  addresses:\n                  - email: user@example.com\n")
+
+	examples, err := fiberopenapi.Examples(r)
+	require.NoError(t, err)
+	assert.Equal(t, "Jane", examples[0].Request.Value.(map[string]any)["customer"], "expected the injected examples to be used")
+}
+
+func TestWriteExamples(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, fiberopenapi.WriteExamples(newExamplesRouter(fiberopenapi.Config{}), dir))
+
+	request, err := os.ReadFile(filepath.Join(dir, "createOrder", "request.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(request), `"customer": "Jane"`)
+	assert.FileExists(t, filepath.Join(dir, "createOrder", "201.json"))
+	assert.FileExists(t, filepath.Join(dir, "createOrder", "400.json"))
+	assert.NoDirExists(t, filepath.Join(dir, "deleteOrdersById"), "expected no directory for operations without bodies")
+}
+
+func TestWriteExamples_NameCollision(t *testing.T) {
+	r := fiberopenapi.NewRouter(fiber.New())
+	r.Get("/orders", PingHandler).With(
+		option.OperationID("getOrdersById"),
+		option.Response(200, new([]Order)),
+	)
+	r.Get("/orders/:id", PingHandler).With(option.Response(200, new(Order)))
+
+	dir := t.TempDir()
+	err := fiberopenapi.WriteExamples(r, dir)
+	require.EqualError(t, err, `operations GET /orders and GET /orders/{id} have the same example name "getOrdersById"`)
+	assert.NoDirExists(t, filepath.Join(dir, "getOrdersById"), "expected nothing to be written")
+}
@@ -0,0 +1,41 @@
+package fiberopenapi
+
+import (
+	"fmt"
+	"os"
+)
+
+// ExportEnv is the environment variable requesting ExportIfRequested to
+// export the specification, set to the path of the file to write.
+const ExportEnv = "FIBEROPENAPI_EXPORT"
+
+// Export validates the specification of gen and writes it to path, in JSON
+// if path ends with ".json" and in YAML if it ends with ".yaml" or ".yml".
+func Export(gen Generator, path string) error {
+	if err := gen.Validate(); err != nil {
+		return fmt.Errorf("invalid OpenAPI specification: %w", err)
+	}
+	return gen.WriteSchemaTo(path)
+}
+
+// ExportIfRequested exports the specification of gen and exits when the
+// FIBEROPENAPI_EXPORT environment variable is set, and returns otherwise.
+//
+// Called once the routes are registered and before the app listens, it
+// produces the specification without binding a port, for example in CI:
+//
+//	FIBEROPENAPI_EXPORT=openapi.yaml go run .
+//
+// The process exits with status 1 if the specification is invalid or can
+// not be written, and 0 otherwise.
+func ExportIfRequested(gen Generator) {
+	path := os.Getenv(ExportEnv)
+	if path == "" {
+		return
+	}
+	if err := Export(gen, path); err != nil {
+		fmt.Fprintf(os.Stderr, "fiberopenapi: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
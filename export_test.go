@@ -0,0 +1,71 @@
+package fiberopenapi_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi"
+	"github.com/oaswrap/spec/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newExportRouter(valid bool) fiberopenapi.Generator {
+	r := fiberopenapi.NewRouter(fiber.New(), option.WithTitle("Test API Export"))
+	r.Get("/ping", PingHandler).With(option.Summary("Ping Endpoint"))
+	if !valid {
+		r.Connect("/tunnel", PingHandler)
+	}
+	return r
+}
+
+func TestExport(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "openapi.json")
+	require.NoError(t, fiberopenapi.Export(newExportRouter(true), path))
+	schema, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(schema), `"title": "Test API Export"`)
+
+	path = filepath.Join(dir, "invalid.yaml")
+	err = fiberopenapi.Export(newExportRouter(false), path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CONNECT /tunnel")
+	assert.NoFileExists(t, path)
+}
+
+func TestExportIfRequested(t *testing.T) {
+	if helper := os.Getenv("EXPORT_HELPER"); helper != "" {
+		fiberopenapi.ExportIfRequested(newExportRouter(helper == "valid"))
+		os.Exit(3) // Not requested.
+	}
+
+	run := func(t *testing.T, helper, path string) int {
+		t.Helper()
+		cmd := exec.Command(os.Args[0], "-test.run=^TestExportIfRequested$")
+		cmd.Env = append(os.Environ(), "EXPORT_HELPER="+helper)
+		if path != "" {
+			cmd.Env = append(cmd.Env, fiberopenapi.ExportEnv+"="+path)
+		}
+		err := cmd.Run()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		require.NoError(t, err)
+		return 0
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "openapi.yaml")
+	assert.Equal(t, 0, run(t, "valid", path), "expected the export to succeed")
+	assert.FileExists(t, path)
+
+	assert.Equal(t, 1, run(t, "invalid", filepath.Join(dir, "invalid.yaml")), "expected the export to fail")
+	assert.Equal(t, 3, run(t, "valid", ""), "expected the program to go on without the variable")
+}
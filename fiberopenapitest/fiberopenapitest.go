@@ -0,0 +1,156 @@
+// Package fiberopenapitest provides test helpers for the applications
+// documented with fiberopenapi: golden files of the specification,
+// validation, breaking changes, and checks that the documented routes
+// exist in Fiber.
+package fiberopenapitest
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/oaswrap/fiberopenapi"
+	"gopkg.in/yaml.v3"
+)
+
+var updateGolden = flag.Bool("update-openapi", false, "update the OpenAPI golden files")
+
+// AssertValid fails the test when the specification of gen is invalid,
+// listing the errors reported by Validate. It reports whether it is valid.
+func AssertValid(t testing.TB, gen fiberopenapi.Generator) bool {
+	t.Helper()
+
+	err := gen.Validate()
+	if err == nil {
+		return true
+	}
+	t.Errorf("invalid OpenAPI specification:\n  %s", strings.ReplaceAll(err.Error(), "\n", "\n  "))
+	return false
+}
+
+// AssertGolden fails the test when the specification of gen differs from
+// the golden file at path, printing the differences. The specification is
+// generated in JSON if path ends with ".json" and in YAML otherwise, and
+// compared semantically, so the formatting of the golden file is not
+// significant.
+//
+// When the tests run with the -update-openapi flag, or with an -update flag
+// defined by the test binary, the golden file is written instead:
+//
+//	go test ./... -update-openapi
+//
+// It reports whether the specification matches the golden file.
+func AssertGolden(t testing.TB, gen fiberopenapi.Generator, path string) bool {
+	t.Helper()
+
+	format := "yaml"
+	if strings.HasSuffix(path, ".json") {
+		format = "json"
+	}
+	got, err := gen.GenerateOpenAPISchema(format)
+	if err != nil {
+		t.Errorf("failed to generate the OpenAPI specification: %v", err)
+		return false
+	}
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Errorf("failed to create the directory of golden file %s: %v", path, err)
+			return false
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Errorf("failed to write golden file %s: %v", path, err)
+			return false
+		}
+		t.Logf("updated golden file %s", path)
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Errorf("golden file %s does not exist, run the tests with -update-openapi to create it", path)
+		return false
+	}
+	if err != nil {
+		t.Errorf("failed to read golden file %s: %v", path, err)
+		return false
+	}
+	if bytes.Equal(want, got) {
+		return true
+	}
+
+	wantObj, err := decode(want)
+	if err != nil {
+		t.Errorf("failed to parse golden file %s: %v", path, err)
+		return false
+	}
+	gotObj, err := decode(got)
+	if err != nil {
+		t.Errorf("failed to parse the OpenAPI specification: %v", err)
+		return false
+	}
+	if diff := cmp.Diff(wantObj, gotObj); diff != "" {
+		t.Errorf("OpenAPI specification differs from golden file %s (-want +got):\n%s"+
+			"run the tests with -update-openapi to update it", path, diff)
+		return false
+	}
+	return true
+}
+
+// AssertNoBreakingChanges fails the test when the specification of gen has
+// breaking changes compared with the baseline specification file, unless
+// its version is bumped, see fiberopenapi.CompareSpec and
+// fiberopenapi.SpecDiff.VersionBumped. It reports whether the check passed.
+func AssertNoBreakingChanges(t testing.TB, gen fiberopenapi.Generator, baselinePath string) bool {
+	t.Helper()
+
+	diff, err := fiberopenapi.CompareSpec(gen, baselinePath)
+	if err != nil {
+		t.Errorf("failed to compare the OpenAPI specification with %s: %v", baselinePath, err)
+		return false
+	}
+	breaking := diff.Breaking()
+	if len(breaking) == 0 || diff.VersionBumped() {
+		return true
+	}
+
+	var sb strings.Builder
+	for _, c := range breaking {
+		sb.WriteString("\n  " + c.String())
+	}
+	t.Errorf("breaking changes to the OpenAPI specification without a version bump from %s (%s):%s",
+		diff.BaselineVersion, baselinePath, sb.String())
+	return false
+}
+
+// updating reports whether the golden files are updated, with the
+// -update-openapi flag or an -update flag defined by the test binary.
+func updating() bool {
+	if *updateGolden {
+		return true
+	}
+	f := flag.Lookup("update")
+	if f == nil {
+		return false
+	}
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+	update, _ := getter.Get().(bool)
+	return update
+}
+
+// decode decodes a JSON or YAML document.
+func decode(data []byte) (any, error) {
+	var v any
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
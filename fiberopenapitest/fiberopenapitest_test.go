@@ -0,0 +1,141 @@
+package fiberopenapitest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi"
+	"github.com/oaswrap/fiberopenapi/fiberopenapitest"
+	"github.com/oaswrap/spec/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// recordingT records the failures of a test helper.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *recordingT) Logf(format string, args ...any) {}
+
+func PingHandler(c *fiber.Ctx) error {
+	return c.SendString("pong")
+}
+
+func newRouter(title string) fiberopenapi.Generator {
+	r := fiberopenapi.NewRouter(fiber.New(), option.WithTitle(title), option.WithVersion("1.0.0"))
+	r.Get("/ping", PingHandler).With(option.Summary("Ping Endpoint"))
+	return r
+}
+
+func TestAssertValid(t *testing.T) {
+	rt := &recordingT{TB: t}
+	assert.True(t, fiberopenapitest.AssertValid(rt, newRouter("Test API")))
+	assert.Empty(t, rt.errors)
+
+	r := newRouter("Test API")
+	r.Connect("/tunnel", PingHandler)
+	assert.False(t, fiberopenapitest.AssertValid(rt, r))
+	require.Len(t, rt.errors, 1)
+	assert.Contains(t, rt.errors[0], "CONNECT /tunnel")
+}
+
+func TestAssertNoBreakingChanges(t *testing.T) {
+	type ListPetsRequest struct {
+		Status string `query:"status" required:"true"`
+	}
+	newVersion := func(version string, breaking bool) fiberopenapi.Generator {
+		r := fiberopenapi.NewRouter(fiber.New(), option.WithVersion(version))
+		if breaking {
+			r.Get("/pets", PingHandler).With(option.Request(new(ListPetsRequest)))
+		} else {
+			r.Get("/pets", PingHandler)
+			r.Delete("/pets/:id", PingHandler)
+		}
+		return r
+	}
+	baseline := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, newVersion("1.0.0", false).WriteSchemaTo(baseline))
+
+	t.Run("unchanged", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		assert.True(t, fiberopenapitest.AssertNoBreakingChanges(rt, newVersion("1.0.0", false), baseline))
+		assert.Empty(t, rt.errors)
+	})
+	t.Run("breaking changes", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		assert.False(t, fiberopenapitest.AssertNoBreakingChanges(rt, newVersion("1.1.0", true), baseline))
+		require.Len(t, rt.errors, 1)
+		assert.Contains(t, rt.errors[0], "without a version bump from 1.0.0")
+		assert.Contains(t, rt.errors[0], "DELETE /pets/{id}: operation removed (breaking)")
+		assert.Contains(t, rt.errors[0], "GET /pets: required parameter query status added (breaking)")
+	})
+	t.Run("breaking changes with a version bump", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		assert.True(t, fiberopenapitest.AssertNoBreakingChanges(rt, newVersion("2.0.0", true), baseline))
+		assert.Empty(t, rt.errors)
+	})
+	t.Run("missing baseline", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		assert.False(t, fiberopenapitest.AssertNoBreakingChanges(rt, newVersion("1.0.0", false), "missing.yaml"))
+		assert.Len(t, rt.errors, 1)
+	})
+}
+
+func TestAssertGolden(t *testing.T) {
+	t.Run("matching golden files", func(t *testing.T) {
+		fiberopenapitest.AssertGolden(t, newRouter("Test API"), filepath.Join("testdata", "golden.yaml"))
+		fiberopenapitest.AssertGolden(t, newRouter("Test API"), filepath.Join("testdata", "golden.json"))
+	})
+	if *update {
+		t.Skip("golden files are written when updating")
+	}
+	t.Run("different golden file", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		assert.False(t, fiberopenapitest.AssertGolden(rt, newRouter("Other API"), filepath.Join("testdata", "golden.yaml")))
+		require.Len(t, rt.errors, 1)
+		assert.Contains(t, rt.errors[0], "(-want +got)")
+		assert.Contains(t, rt.errors[0], `string("Test API")`)
+		assert.Contains(t, rt.errors[0], `string("Other API")`)
+	})
+	t.Run("formatting is not significant", func(t *testing.T) {
+		want, err := os.ReadFile(filepath.Join("testdata", "golden.json"))
+		require.NoError(t, err)
+		var compact bytes.Buffer
+		require.NoError(t, json.Compact(&compact, want))
+		path := filepath.Join(t.TempDir(), "compact.json")
+		require.NoError(t, os.WriteFile(path, compact.Bytes(), 0644))
+		fiberopenapitest.AssertGolden(t, newRouter("Test API"), path)
+	})
+	t.Run("missing golden file", func(t *testing.T) {
+		rt := &recordingT{TB: t}
+		assert.False(t, fiberopenapitest.AssertGolden(rt, newRouter("Test API"), filepath.Join(t.TempDir(), "missing.yaml")))
+		require.Len(t, rt.errors, 1)
+		assert.Contains(t, rt.errors[0], "-update-openapi")
+	})
+	t.Run("update flag", func(t *testing.T) {
+		require.NoError(t, flag.Set("update-openapi", "true"))
+		defer func() { _ = flag.Set("update-openapi", "false") }()
+
+		path := filepath.Join(t.TempDir(), "nested", "openapi.yaml")
+		assert.True(t, fiberopenapitest.AssertGolden(t, newRouter("Updated API"), path))
+		schema, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(schema), "title: Updated API")
+	})
+}
@@ -0,0 +1,223 @@
+package fiberopenapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi"
+	"github.com/oaswrap/fiberopenapi/internal/util"
+)
+
+// matchedHeader is set by the handlers of the copy of the app built by
+// AssertRoutesExist, to tell a matched route from a not found error.
+const matchedHeader = "X-Fiberopenapitest-Route"
+
+// Requests returns a request for every operation documented by gen, sorted
+// by path and method. The path parameters are set to sample values valid for
+// their schemas, for example "1" for an integer or the first value of an enum.
+//
+// The requests have no body; they can be fired through app.Test to smoke
+// test the routes of an app.
+func Requests(t testing.TB, gen fiberopenapi.Generator) []*http.Request {
+	t.Helper()
+
+	schema, err := gen.MarshalJSON()
+	if err != nil {
+		t.Errorf("failed to generate the OpenAPI specification: %v", err)
+		return nil
+	}
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		t.Errorf("failed to parse the OpenAPI specification: %v", err)
+		return nil
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var reqs []*http.Request
+	for _, path := range paths {
+		item := doc.Paths[path]
+		var itemParams []parameter
+		_ = json.Unmarshal(item["parameters"], &itemParams)
+		for _, method := range util.OperationMethods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+			var op struct {
+				Parameters []parameter `json:"parameters"`
+			}
+			_ = json.Unmarshal(raw, &op)
+			target := samplePath(path, append(itemParams, op.Parameters...))
+			reqs = append(reqs, httptest.NewRequest(strings.ToUpper(method), target, nil))
+		}
+	}
+	return reqs
+}
+
+// AssertRoutesExist fails the test when an operation documented by gen has
+// no matching route in app, for example a route documented on a router that
+// is not the one of the app.
+//
+// The requests of Requests are fired through app.Test of a copy of app
+// whose routes have empty handlers, so the handlers and middleware of app
+// are not run. It reports whether every documented route exists.
+func AssertRoutesExist(t testing.TB, app *fiber.App, gen fiberopenapi.Generator) bool {
+	t.Helper()
+
+	reqs := Requests(t, gen)
+
+	// The routes of the mounted apps are added to app on startup.
+	app.Handler()
+	cfg := app.Config()
+	stub := fiber.New(fiber.Config{
+		CaseSensitive:         cfg.CaseSensitive,
+		StrictRouting:         cfg.StrictRouting,
+		UnescapePath:          cfg.UnescapePath,
+		DisableStartupMessage: true,
+	})
+	for _, route := range app.GetRoutes(true) {
+		stub.Add(route.Method, route.Path, func(c *fiber.Ctx) error {
+			c.Set(matchedHeader, c.Route().Path)
+			return c.SendStatus(fiber.StatusNoContent)
+		})
+	}
+
+	var missing []string
+	for _, req := range reqs {
+		resp, err := stub.Test(req, -1)
+		if err != nil {
+			t.Errorf("failed to test route %s %s: %v", req.Method, req.URL.Path, err)
+			return false
+		}
+		_ = resp.Body.Close()
+		if resp.Header.Get(matchedHeader) == "" {
+			missing = append(missing, req.Method+" "+req.URL.Path)
+		}
+	}
+	if len(missing) > 0 {
+		t.Errorf("documented routes not registered on the Fiber app:\n  %s", strings.Join(missing, "\n  "))
+		return false
+	}
+	return true
+}
+
+// parameter is a parameter of an operation, the references to components
+// being ignored.
+type parameter struct {
+	Name   string  `json:"name"`
+	In     string  `json:"in"`
+	Schema *schema `json:"schema"`
+}
+
+// schema is the schema of a path parameter.
+type schema struct {
+	Type      any      `json:"type"`
+	Format    string   `json:"format"`
+	Pattern   string   `json:"pattern"`
+	Enum      []any    `json:"enum"`
+	Minimum   *float64 `json:"minimum"`
+	Maximum   *float64 `json:"maximum"`
+	MinLength *int     `json:"minLength"`
+}
+
+// samplePath replaces the parameters of the path template with sample values.
+func samplePath(path string, params []parameter) string {
+	schemas := make(map[string]*schema)
+	for _, p := range params {
+		if p.In == "path" {
+			schemas[p.Name] = p.Schema
+		}
+	}
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(path, '{')
+		end := strings.IndexByte(path, '}')
+		if start < 0 || end < start {
+			sb.WriteString(path)
+			return sb.String()
+		}
+		sb.WriteString(path[:start])
+		sb.WriteString(url.PathEscape(sampleValue(schemas[path[start+1:end]])))
+		path = path[end+1:]
+	}
+}
+
+// patternCandidates are the values tried for the parameters with a pattern.
+var patternCandidates = []string{"a", "1", "a1", "A", "abc", "123", "a-1", "a_1"}
+
+// sampleValue returns a value valid for the schema of a path parameter.
+func sampleValue(s *schema) string {
+	if s == nil {
+		return "a"
+	}
+	if len(s.Enum) > 0 {
+		return fmt.Sprint(s.Enum[0])
+	}
+	switch s.typ() {
+	case "integer", "number":
+		n := 1.0
+		switch {
+		case s.Minimum != nil:
+			n = math.Ceil(*s.Minimum)
+		case s.Maximum != nil && *s.Maximum < n:
+			n = math.Floor(*s.Maximum)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	case "boolean":
+		return "true"
+	}
+	switch s.Format {
+	case "uuid":
+		return "123e4567-e89b-12d3-a456-426614174000"
+	case "date-time":
+		return "2006-01-02T15:04:05Z"
+	case "date":
+		return "2006-01-02"
+	}
+	if s.Pattern != "" {
+		if re, err := regexp.Compile(s.Pattern); err == nil {
+			for _, v := range patternCandidates {
+				if re.MatchString(v) {
+					return v
+				}
+			}
+		}
+	}
+	if s.MinLength != nil && *s.MinLength > 1 {
+		return strings.Repeat("a", *s.MinLength)
+	}
+	return "a"
+}
+
+// typ returns the type of the schema, the first non-null type for the list
+// of types of OpenAPI 3.1.
+func (s *schema) typ() string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []any:
+		for _, e := range t {
+			if e, ok := e.(string); ok && e != "null" {
+				return e
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package fiberopenapitest_test
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi"
+	"github.com/oaswrap/fiberopenapi/fiberopenapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequests(t *testing.T) {
+	r := fiberopenapi.NewRouter(fiber.New())
+	r.Get("/users/:id<int;min(5)>", PingHandler)
+	r.Delete("/users/:id<int;min(5)>", PingHandler)
+	r.Get("/files/:id<guid>/:name<alpha>", PingHandler)
+	r.Get("/codes/:code<len(3)>", PingHandler)
+	r.Get("/flags/:on<bool>", PingHandler)
+
+	var got []string
+	for _, req := range fiberopenapitest.Requests(t, r) {
+		got = append(got, req.Method+" "+req.URL.Path)
+	}
+	assert.Equal(t, []string{
+		"GET /codes/aaa",
+		"GET /files/123e4567-e89b-12d3-a456-426614174000/a",
+		"GET /flags/true",
+		"GET /users/5",
+		"DELETE /users/5",
+	}, got)
+}
+
+func TestAssertRoutesExist(t *testing.T) {
+	called := 0
+	handler := func(c *fiber.Ctx) error {
+		called++
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+
+	app := fiber.New()
+	r := fiberopenapi.NewRouter(app)
+	r.Use(func(c *fiber.Ctx) error {
+		called++
+		return c.Next()
+	})
+	r.Get("/users/:id<int>", handler)
+	r.Post("/users", handler)
+	r.Route("/admin", func(r fiberopenapi.Router) {
+		r.Put("/settings/:key?", handler)
+	})
+
+	sub := fiberopenapi.NewRouter(fiber.New())
+	sub.Get("/orders/:id", handler)
+	r.Mount("/shop", sub)
+
+	t.Run("routes of the app", func(t *testing.T) {
+		assert.True(t, fiberopenapitest.AssertRoutesExist(t, app, r))
+		assert.Zero(t, called, "expected the handlers of the app not to run")
+	})
+	t.Run("routes of another app", func(t *testing.T) {
+		other := fiber.New()
+		other.Get("/users/:id", handler)
+		other.Put("/admin/settings/:key", handler)
+
+		rt := &recordingT{TB: t}
+		assert.False(t, fiberopenapitest.AssertRoutesExist(rt, other, r))
+		require.Len(t, rt.errors, 1)
+		assert.Equal(t, "documented routes not registered on the Fiber app:\n"+
+			"  PUT /admin/settings\n"+
+			"  GET /shop/orders/a\n"+
+			"  POST /users", rt.errors[0])
+	})
+}
@@ -0,0 +1,82 @@
+package fiberopenapi
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/spec/option"
+)
+
+// HandlerFunc is a typed handler receiving the bound request structure and
+// returning the response serialized as JSON.
+type HandlerFunc[Req, Resp any] func(c *fiber.Ctx, req *Req) (*Resp, error)
+
+// Handle registers a typed handler for the method and path.
+//
+// It documents the route with option.Request(new(Req)) and
+// option.Response(200, new(Resp)), binds and validates the request like
+// Config.BindRequests does, whether it is enabled or not, and serializes the
+// response as JSON. Errors returned by the handler are passed to the Fiber
+// error handler.
+//
+// A nil response sends no body, with the status set on the context or 204 No
+// Content when the handler left it to 200, since the documented 200 response
+// has a body. The 204 response is not documented, declare it with
+// option.Response(204, nil) on the returned route.
+//
+// r must be a router created by this package.
+func Handle[Req, Resp any](r Router, method, path string, h HandlerFunc[Req, Resp]) Route {
+	rr, ok := r.(*router)
+	if !ok {
+		panic(fmt.Sprintf("fiberopenapi: Handle requires a router created by fiberopenapi, got %T", r))
+	}
+
+	rt := rr.add(method, path, true, func(c *fiber.Ctx) error {
+		req := Request[Req](c)
+		if req == nil {
+			// The request structure is declared for another content type.
+			req = new(Req)
+		}
+		resp, err := h(c, req)
+		if err != nil {
+			return err
+		}
+		if resp == nil {
+			if c.Response().StatusCode() == fiber.StatusOK {
+				c.Status(fiber.StatusNoContent)
+			}
+			return c.Send(nil)
+		}
+		return c.JSON(resp)
+	})
+
+	return rt.With(
+		option.Request(new(Req)),
+		option.Response(fiber.StatusOK, new(Resp)),
+	)
+}
+
+// Get registers a typed handler for GET requests, see Handle.
+func Get[Req, Resp any](r Router, path string, h HandlerFunc[Req, Resp]) Route {
+	return Handle(r, fiber.MethodGet, path, h)
+}
+
+// Post registers a typed handler for POST requests, see Handle.
+func Post[Req, Resp any](r Router, path string, h HandlerFunc[Req, Resp]) Route {
+	return Handle(r, fiber.MethodPost, path, h)
+}
+
+// Put registers a typed handler for PUT requests, see Handle.
+func Put[Req, Resp any](r Router, path string, h HandlerFunc[Req, Resp]) Route {
+	return Handle(r, fiber.MethodPut, path, h)
+}
+
+// Patch registers a typed handler for PATCH requests, see Handle.
+func Patch[Req, Resp any](r Router, path string, h HandlerFunc[Req, Resp]) Route {
+	return Handle(r, fiber.MethodPatch, path, h)
+}
+
+// Delete registers a typed handler for DELETE requests, see Handle.
+func Delete[Req, Resp any](r Router, path string, h HandlerFunc[Req, Resp]) Route {
+	return Handle(r, fiber.MethodDelete, path, h)
+}
@@ -0,0 +1,107 @@
+package fiberopenapi_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type CreateGreetingRequest struct {
+	Lang string `query:"lang" enum:"en,fr"`
+	Name string `json:"name" required:"true"`
+}
+
+type Greeting struct {
+	Message string `json:"message"`
+}
+
+func TestHandle(t *testing.T) {
+	app := fiber.New()
+	r := fiberopenapi.NewRouter(app)
+
+	api := r.Group("/api")
+	fiberopenapi.Post(api, "/greetings", func(c *fiber.Ctx, req *CreateGreetingRequest) (*Greeting, error) {
+		if req.Name == "nobody" {
+			return nil, fiber.NewError(fiber.StatusNotFound, "nobody to greet")
+		}
+		greeting := "Hello, "
+		if req.Lang == "fr" {
+			greeting = "Bonjour, "
+		}
+		return &Greeting{Message: greeting + req.Name}, nil
+	}).Name("greetings.create")
+	fiberopenapi.Delete(api, "/greetings/:id", func(c *fiber.Ctx, req *struct {
+		ID int `path:"id"`
+	}) (*Greeting, error) {
+		c.Status(fiber.StatusAccepted)
+		return nil, nil
+	})
+	fiberopenapi.Put(api, "/greetings/:id", func(c *fiber.Ctx, req *struct {
+		ID int `path:"id"`
+	}) (*Greeting, error) {
+		return nil, nil
+	})
+
+	do := func(t *testing.T, req *http.Request) (*http.Response, string) {
+		t.Helper()
+		res, err := app.Test(req, -1)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res, string(body)
+	}
+
+	t.Run("binds the request and serializes the response", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/api/greetings?lang=fr", strings.NewReader(`{"name":"Ada"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		res, body := do(t, req)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.JSONEq(t, `{"message":"Bonjour, Ada"}`, body)
+	})
+
+	t.Run("validates the request", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/api/greetings?lang=de", strings.NewReader(`{}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		res, _ := do(t, req)
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+	})
+
+	t.Run("returns handler errors", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/api/greetings", strings.NewReader(`{"name":"nobody"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		res, body := do(t, req)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		assert.Equal(t, "nobody to greet", body)
+	})
+
+	t.Run("sends no body for nil responses", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, "/api/greetings/1", nil)
+		res, body := do(t, req)
+		assert.Equal(t, http.StatusAccepted, res.StatusCode, "expected the status set by the handler to be kept")
+		assert.Empty(t, body)
+
+		req, _ = http.NewRequest(http.MethodPut, "/api/greetings/1", nil)
+		res, body = do(t, req)
+		assert.Equal(t, http.StatusNoContent, res.StatusCode, "expected no empty 200 response")
+		assert.Empty(t, body)
+	})
+
+	t.Run("documents the request and response", func(t *testing.T) {
+		schema, err := r.MarshalYAML()
+		require.NoError(t, err)
+		assert.Contains(t, string(schema), "$ref: '#/components/schemas/FiberopenapiTestCreateGreetingRequest'")
+		assert.Contains(t, string(schema), "$ref: '#/components/schemas/FiberopenapiTestGreeting'")
+		assert.Contains(t, string(schema), "name: lang")
+	})
+
+	t.Run("names the route", func(t *testing.T) {
+		assert.Equal(t, "/api/greetings", app.GetRoute("greetings.create").Path)
+	})
+}
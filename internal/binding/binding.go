@@ -0,0 +1,467 @@
+package binding
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/spec/openapi"
+	"github.com/swaggest/jsonschema-go"
+)
+
+// Parameter locations, named after the struct tags read by the spec reflector.
+const (
+	InPath     = "path"
+	InQuery    = "query"
+	InHeader   = "header"
+	InCookie   = "cookie"
+	InFormData = "formData"
+	InBody     = "body"
+
+	tagJSON = "json"
+)
+
+// paramLocations lists the parameter locations in the order the tags are looked up.
+var paramLocations = []string{InPath, InQuery, InHeader, InCookie, InFormData}
+
+// Binder binds requests to their request structures and validates them
+// against the JSON schema of the structures.
+//
+// The schemas are reflected once per structure type, with the same reflector
+// options as the specification.
+type Binder struct {
+	reflector *jsonschema.Reflector
+	opts      []func(*jsonschema.ReflectContext)
+
+	mu      sync.Mutex
+	plans   map[reflect.Type]*plan
+	schemas map[reflect.Type]*jsonschema.Schema // JSON document schemas, see ValidateJSON
+}
+
+// New creates a Binder using the reflector configuration of the specification.
+func New(cfg *openapi.ReflectorConfig) *Binder {
+	b := &Binder{
+		reflector: &jsonschema.Reflector{},
+		opts:      []func(*jsonschema.ReflectContext){jsonschema.InlineRefs},
+		plans:     make(map[reflect.Type]*plan),
+		schemas:   make(map[reflect.Type]*jsonschema.Schema),
+	}
+	if cfg == nil {
+		return b
+	}
+	for _, m := range cfg.TypeMappings {
+		b.reflector.AddTypeMapping(m.Src, m.Dst)
+	}
+	if cfg.InterceptPropFunc != nil {
+		b.opts = append(b.opts, jsonschema.InterceptProp(func(params jsonschema.InterceptPropParams) error {
+			return cfg.InterceptPropFunc(openapi.InterceptPropParams{
+				Context:        params.Context,
+				Path:           params.Path,
+				Name:           params.Name,
+				Field:          params.Field,
+				PropertySchema: params.PropertySchema,
+				ParentSchema:   params.ParentSchema,
+				Processed:      params.Processed,
+			})
+		}))
+	}
+	if cfg.InterceptSchemaFunc != nil {
+		b.opts = append(b.opts, jsonschema.InterceptSchema(func(params jsonschema.InterceptSchemaParams) (bool, error) {
+			return cfg.InterceptSchemaFunc(openapi.InterceptSchemaParams{
+				Context:   params.Context,
+				Value:     params.Value,
+				Schema:    params.Schema,
+				Processed: params.Processed,
+			})
+		}))
+	}
+	return b
+}
+
+// plan describes how to bind a request structure type.
+type plan struct {
+	typ     reflect.Type
+	fields  []field
+	body    bool // the structure has a JSON body
+	form    bool // the structure has formData fields
+	schemas map[string]*jsonschema.Schema
+}
+
+// field is a parameter field of a request structure.
+type field struct {
+	in    string
+	name  string
+	index []int
+	sep   string // separator of the items of an array, see separator
+}
+
+// Bind creates a new value of the type of structure and binds the request to it.
+//
+// pathParams maps the OpenAPI names of the path parameters to the names
+// Fiber uses for them; parameters missing from it keep their name.
+// The returned value is a pointer to the bound structure.
+//
+// Bind returns an *Error with status 400 if the request can not be decoded,
+// or 422 if it does not match the schema of the structure.
+func (b *Binder) Bind(c *fiber.Ctx, structure any, pathParams map[string]string) (any, error) {
+	p, err := b.plan(structure)
+	if err != nil {
+		return nil, err
+	}
+
+	dst := reflect.New(p.typ)
+	values := make(map[string]map[string]any)
+	var errs []FieldError
+
+	form := p.form && isForm(c)
+	if form {
+		if _, err := multipartForm(c); err != nil {
+			return nil, &Error{Status: fiber.StatusBadRequest, Detail: "malformed form data: " + err.Error()}
+		}
+	}
+
+	for _, f := range p.fields {
+		if f.in == InFormData && !form {
+			continue
+		}
+		fv := fieldByIndex(dst.Elem(), f.index)
+		present, err := b.bindField(c, f, fv, pathParams)
+		if err != nil {
+			errs = append(errs, FieldError{In: f.in, Field: f.name, Message: err.Error()})
+			continue
+		}
+		if !present {
+			continue
+		}
+		if values[f.in] == nil {
+			values[f.in] = make(map[string]any)
+		}
+		if f.in == InFormData && isFileField(fv.Type()) {
+			values[f.in][f.name] = upload{}
+		} else {
+			values[f.in][f.name] = jsonValue(fv.Interface())
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &Error{Status: fiber.StatusBadRequest, Detail: "invalid request parameters", Errors: errs}
+	}
+
+	var body any
+	bindBody := p.body && !form && hasBody(c.Method())
+	if bindBody {
+		raw := c.Body()
+		if len(raw) == 0 {
+			if p.typ.Kind() == reflect.Struct {
+				// A missing body is validated as an empty object, so
+				// that its required fields are reported.
+				body = map[string]any{}
+			}
+		} else {
+			if ct := c.Get(fiber.HeaderContentType); ct != "" && !c.Is("json") {
+				return nil, &Error{
+					Status: fiber.StatusUnsupportedMediaType,
+					Detail: fmt.Sprintf("unsupported content type %q, expected application/json", ct),
+				}
+			}
+			if err := json.Unmarshal(raw, &body); err != nil {
+				return nil, &Error{Status: fiber.StatusBadRequest, Detail: "malformed JSON body: " + err.Error()}
+			}
+		}
+	}
+
+	for _, in := range paramLocations {
+		if s := p.schemas[in]; s != nil {
+			obj := make(map[string]any, len(values[in]))
+			for k, v := range values[in] {
+				obj[k] = v
+			}
+			validate(s, in, "", obj, &errs)
+		}
+	}
+	if s := p.schemas[InBody]; s != nil && body != nil {
+		validate(s, InBody, "", body, &errs)
+	}
+	if len(errs) > 0 {
+		return nil, &Error{Status: fiber.StatusUnprocessableEntity, Detail: "request does not match the schema", Errors: errs}
+	}
+
+	if bindBody && len(c.Body()) > 0 {
+		if err := json.Unmarshal(c.Body(), dst.Interface()); err != nil {
+			return nil, &Error{Status: fiber.StatusBadRequest, Detail: "malformed JSON body: " + err.Error()}
+		}
+		// Decoding the body may overwrite parameters sharing a field, bind them again.
+		for _, f := range p.fields {
+			if f.in != InFormData {
+				_, _ = b.bindField(c, f, fieldByIndex(dst.Elem(), f.index), pathParams)
+			}
+		}
+	}
+
+	return dst.Interface(), nil
+}
+
+// bindField sets the field from the request and reports whether the request has a value for it.
+func (b *Binder) bindField(c *fiber.Ctx, f field, fv reflect.Value, pathParams map[string]string) (bool, error) {
+	if f.in == InFormData && isFileField(fv.Type()) {
+		form, _ := multipartForm(c)
+		if form == nil || len(form.File[f.name]) == 0 {
+			return false, nil
+		}
+		setFiles(fv, form.File[f.name])
+		return true, nil
+	}
+
+	raws := rawValues(c, f, pathParams)
+	if len(raws) == 0 {
+		return false, nil
+	}
+	return true, setValue(fv, raws, f.sep)
+}
+
+func rawValues(c *fiber.Ctx, f field, pathParams map[string]string) []string {
+	var raws []string
+	switch f.in {
+	case InPath:
+		name := f.name
+		if fiberName, ok := pathParams[name]; ok {
+			name = fiberName
+		}
+		if v := c.Params(name); v != "" {
+			raws = append(raws, v)
+		}
+	case InQuery:
+		for _, v := range c.Context().QueryArgs().PeekMulti(f.name) {
+			raws = append(raws, string(v))
+		}
+	case InHeader:
+		for _, v := range c.Request().Header.PeekAll(f.name) {
+			raws = append(raws, string(v))
+		}
+	case InCookie:
+		if v := c.Cookies(f.name); v != "" {
+			raws = append(raws, v)
+		}
+	case InFormData:
+		if form, _ := multipartForm(c); form != nil {
+			raws = append(raws, form.Value[f.name]...)
+		} else {
+			for _, v := range c.Request().PostArgs().PeekMulti(f.name) {
+				raws = append(raws, string(v))
+			}
+		}
+	}
+	return raws
+}
+
+func isForm(c *fiber.Ctx) bool {
+	ct := c.Get(fiber.HeaderContentType)
+	return strings.HasPrefix(ct, fiber.MIMEApplicationForm) || strings.HasPrefix(ct, fiber.MIMEMultipartForm)
+}
+
+// multipartForm returns the multipart form of the request, or nil if the
+// request is not a multipart request.
+func multipartForm(c *fiber.Ctx) (*multipart.Form, error) {
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		return nil, nil
+	}
+	return c.MultipartForm()
+}
+
+// hasBody reports whether requests with the method are documented with a body.
+func hasBody(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete, http.MethodTrace:
+		return false
+	}
+	return true
+}
+
+// plan returns the binding plan of the structure type, building it on first use.
+func (b *Binder) plan(structure any) (*plan, error) {
+	t := reflect.TypeOf(structure)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil, fmt.Errorf("binding: nil request structure")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if p, ok := b.plans[t]; ok {
+		return p, nil
+	}
+
+	p := &plan{typ: t, schemas: make(map[string]*jsonschema.Schema)}
+	if t.Kind() == reflect.Struct {
+		collectFields(t, nil, p)
+	} else {
+		// Maps and slices can only be sent as a JSON body.
+		p.body = true
+	}
+
+	v := reflect.New(t).Interface()
+	for _, in := range paramLocations {
+		if !p.has(in) {
+			continue
+		}
+		s, err := b.reflect(v, jsonschema.PropertyNameTag(in))
+		if err != nil {
+			return nil, err
+		}
+		if in == InPath {
+			// Path parameters are always required.
+			for name := range s.Properties {
+				s.Required = append(s.Required, name)
+			}
+		}
+		p.schemas[in] = s
+	}
+	if p.form {
+		// A structure with formData fields has no JSON body, as in the specification.
+		p.body = false
+	}
+	if p.body {
+		s, err := b.reflect(v)
+		if err != nil {
+			return nil, err
+		}
+		p.schemas[InBody] = s
+	}
+
+	b.plans[t] = p
+	return p, nil
+}
+
+// ValidateJSON validates the JSON document data against the schema of
+// structure and returns the violations, reported in the location in.
+//
+// It returns an error if data is not valid JSON.
+func (b *Binder) ValidateJSON(structure any, in string, data []byte) ([]FieldError, error) {
+	s, err := b.jsonSchema(structure)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("malformed JSON: %w", err)
+	}
+	var errs []FieldError
+	validate(s, in, "", v, &errs)
+	return errs, nil
+}
+
+func (b *Binder) jsonSchema(structure any) (*jsonschema.Schema, error) {
+	t := reflect.TypeOf(structure)
+	if t == nil {
+		return nil, fmt.Errorf("binding: nil structure")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if s, ok := b.schemas[t]; ok {
+		return s, nil
+	}
+	s, err := b.reflect(structure)
+	if err != nil {
+		return nil, err
+	}
+	b.schemas[t] = s
+	return s, nil
+}
+
+func (b *Binder) reflect(v any, opts ...func(*jsonschema.ReflectContext)) (*jsonschema.Schema, error) {
+	s, err := b.reflector.Reflect(v, append(append([]func(*jsonschema.ReflectContext){}, b.opts...), opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("binding: reflect %T: %w", v, err)
+	}
+	return &s, nil
+}
+
+func (p *plan) has(in string) bool {
+	for _, f := range p.fields {
+		if f.in == in {
+			return true
+		}
+	}
+	return false
+}
+
+// collectFields records the parameter fields of t, including embedded structs,
+// and whether t has JSON or formData fields.
+func collectFields(t reflect.Type, index []int, p *plan) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		idx := append(append([]int{}, index...), i)
+
+		if name, in, ok := paramTag(sf); ok {
+			if sf.IsExported() && name != "-" {
+				p.fields = append(p.fields, field{in: in, name: name, index: idx, sep: separator(sf, in)})
+				if in == InFormData {
+					p.form = true
+				}
+			}
+			continue
+		}
+		if name, ok := sf.Tag.Lookup(tagJSON); ok {
+			if name != "-" && sf.IsExported() {
+				p.body = true
+			}
+			continue
+		}
+		if sf.Anonymous {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectFields(ft, idx, p)
+			}
+		}
+	}
+}
+
+// paramTag returns the parameter name and location declared by the field tags.
+func paramTag(sf reflect.StructField) (name, in string, ok bool) {
+	for _, in := range paramLocations {
+		if name, ok := sf.Tag.Lookup(in); ok {
+			return strings.Split(name, ",")[0], in, true
+		}
+	}
+	return "", "", false
+}
+
+// fieldByIndex returns the field at index, allocating nil embedded pointers on the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// jsonValue converts v to the value it has once encoded to JSON,
+// the form the schema validation works on.
+func jsonValue(v any) any {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return v
+	}
+	return out
+}
@@ -0,0 +1,137 @@
+package binding
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestSetValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		raws    []string
+		sep     string
+		want    any
+		wantErr string
+	}{
+		{name: "string", raws: []string{"abc"}, want: "abc"},
+		{name: "int", raws: []string{"-42"}, want: int32(-42)},
+		{name: "int overflow", raws: []string{"4294967296"}, want: int32(0), wantErr: "must be an integer"},
+		{name: "uint", raws: []string{"42"}, want: uint(42)},
+		{name: "negative uint", raws: []string{"-1"}, want: uint(0), wantErr: "must be a non-negative integer"},
+		{name: "float", raws: []string{"1.5"}, want: 1.5},
+		{name: "bool", raws: []string{"true"}, want: true},
+		{name: "invalid bool", raws: []string{"maybe"}, want: false, wantErr: "must be a boolean"},
+		{name: "pointer", raws: []string{"7"}, want: ptr(7)},
+		{name: "slice of values", raws: []string{"1", "2"}, want: []int{1, 2}},
+		{name: "unsplit slice", raws: []string{"a,b", "c"}, want: []string{"a,b", "c"}},
+		{name: "comma separated slice", raws: []string{"a,b"}, sep: ",", want: []string{"a", "b"}},
+		{name: "comma separated values", raws: []string{"a,b", "c"}, sep: ",", want: []string{"a", "b", "c"}},
+		{name: "pipe separated pointer to slice", raws: []string{"1|2"}, sep: "|", want: &[]int{1, 2}},
+		{name: "text unmarshaler", raws: []string{"2024-01-02T03:04:05Z"}, want: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := reflect.New(reflect.TypeOf(tt.want)).Elem()
+			err := setValue(v, tt.raws, tt.sep)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v.Interface())
+		})
+	}
+}
+
+func TestSetValue_Interface(t *testing.T) {
+	var empty any
+	v := reflect.ValueOf(&empty).Elem()
+	require.NoError(t, setValue(v, []string{"abc"}, ""))
+	assert.Equal(t, "abc", empty)
+
+	var stringer fmt.Stringer
+	v = reflect.ValueOf(&stringer).Elem()
+	require.EqualError(t, setValue(v, []string{"abc"}, ""), "unsupported parameter type fmt.Stringer")
+	assert.Nil(t, stringer)
+}
+
+func TestValidate(t *testing.T) {
+	type Item struct {
+		SKU string `json:"sku" required:"true" pattern:"^[A-Z]{3}$"`
+	}
+	type Order struct {
+		Kind     string   `json:"kind" enum:"retail,wholesale"`
+		Quantity int      `json:"quantity" minimum:"1" exclusiveMaximum:"10"`
+		Weight   float64  `json:"weight" multipleOf:"0.5"`
+		Note     string   `json:"note" minLength:"2" maxLength:"4"`
+		Items    []Item   `json:"items" minItems:"1" maxItems:"2"`
+		Codes    []string `json:"codes" enum:"a,b"`
+		Comment  *string  `json:"comment"`
+	}
+
+	reflector := jsonschema.Reflector{}
+	s, err := reflector.Reflect(Order{}, jsonschema.InlineRefs)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		value string
+		want  []FieldError
+	}{
+		{
+			name:  "valid",
+			value: `{"kind":"retail","quantity":2,"weight":1.5,"note":"abc","items":[{"sku":"ABC"}],"codes":["a"],"comment":null}`,
+		},
+		{
+			name:  "type mismatch",
+			value: `{"quantity":"2","items":{}}`,
+			want: []FieldError{
+				{In: InBody, Field: "items", Message: "must be an array or null"},
+				{In: InBody, Field: "quantity", Message: "must be an integer"},
+			},
+		},
+		{
+			name:  "constraints",
+			value: `{"kind":"other","quantity":10,"weight":1.2,"note":"a","items":[],"codes":["c"]}`,
+			want: []FieldError{
+				{In: InBody, Field: "codes[0]", Message: `must be one of ["a", "b"]`},
+				{In: InBody, Field: "items", Message: "must have at least 1 items"},
+				{In: InBody, Field: "kind", Message: `must be one of ["retail", "wholesale"]`},
+				{In: InBody, Field: "note", Message: "must be at least 2 characters long"},
+				{In: InBody, Field: "quantity", Message: "must be less than 10"},
+				{In: InBody, Field: "weight", Message: "must be a multiple of 0.5"},
+			},
+		},
+		{
+			name:  "nested",
+			value: `{"note":"abcde","items":[{"sku":"abc"},{},{"sku":"XYZ"}]}`,
+			want: []FieldError{
+				{In: InBody, Field: "items", Message: "must have at most 2 items"},
+				{In: InBody, Field: "items[0].sku", Message: "must match pattern ^[A-Z]{3}$"},
+				{In: InBody, Field: "items[1].sku", Message: "is required"},
+				{In: InBody, Field: "note", Message: "must be at most 4 characters long"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			require.NoError(t, json.Unmarshal([]byte(tt.value), &v))
+
+			var errs []FieldError
+			validate(&s, InBody, "", v, &errs)
+			assert.Equal(t, tt.want, errs)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,45 @@
+package binding
+
+import (
+	"strings"
+)
+
+// Error reports a request that can not be bound to its request structure.
+type Error struct {
+	// Status is the HTTP status answering the request: 400 for malformed
+	// input, 415 for an unsupported body and 422 for input that does not
+	// match the schema.
+	Status int
+	// Detail describes the error.
+	Detail string
+	// Errors lists the invalid fields.
+	Errors []FieldError
+}
+
+// FieldError describes an invalid field of the request.
+type FieldError struct {
+	// In is the location of the field: path, query, header, cookie, formData or body.
+	In string `json:"in"`
+	// Field is the name of the parameter, or the path of the property in the body.
+	Field string `json:"field"`
+	// Message describes why the field is invalid.
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Detail)
+	for i, fe := range e.Errors {
+		if i == 0 {
+			sb.WriteString(": ")
+		} else {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(fe.In)
+		if fe.Field != "" {
+			sb.WriteString(" " + fe.Field)
+		}
+		sb.WriteString(" " + fe.Message)
+	}
+	return sb.String()
+}
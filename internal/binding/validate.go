@@ -0,0 +1,269 @@
+package binding
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/swaggest/jsonschema-go"
+)
+
+// patterns caches the compiled schema patterns.
+var patterns sync.Map
+
+// validate checks the JSON value v against the schema s and appends the
+// violations to errs.
+//
+// It supports the keywords the spec reflector derives from struct tags:
+// type, enum, const, required, properties, additionalProperties, items,
+// minimum, maximum, multipleOf, minLength, maxLength, pattern, minItems,
+// maxItems and allOf.
+func validate(s *jsonschema.Schema, in, path string, v any, errs *[]FieldError) {
+	if s == nil {
+		return
+	}
+	if _, ok := v.(upload); ok {
+		return
+	}
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, FieldError{In: in, Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Type != nil && !matchesType(s.Type, v) {
+		fail("must be %s", typeName(s.Type))
+		return
+	}
+	if len(s.Enum) > 0 {
+		if items, ok := v.([]any); ok && !hasArray(s.Enum) {
+			// The reflector puts the enum of a slice field on the array,
+			// it applies to its items.
+			for i, item := range items {
+				if !contains(s.Enum, item) {
+					field := path + "[" + strconv.Itoa(i) + "]"
+					*errs = append(*errs, FieldError{In: in, Field: field, Message: "must be one of " + formatValues(s.Enum)})
+				}
+			}
+		} else if !contains(s.Enum, v) {
+			fail("must be one of %s", formatValues(s.Enum))
+		}
+	}
+	if s.Const != nil && !equal(*s.Const, v) {
+		fail("must be %v", formatValue(*s.Const))
+	}
+	for _, sub := range s.AllOf {
+		validate(sub.TypeObject, in, path, v, errs)
+	}
+
+	switch v := v.(type) {
+	case float64:
+		validateNumber(s, v, fail)
+	case string:
+		validateString(s, v, fail)
+	case []any:
+		if s.MinItems > 0 && int64(len(v)) < s.MinItems {
+			fail("must have at least %d items", s.MinItems)
+		}
+		if s.MaxItems != nil && int64(len(v)) > *s.MaxItems {
+			fail("must have at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil && s.Items.SchemaOrBool != nil {
+			for i, item := range v {
+				validate(s.Items.SchemaOrBool.TypeObject, in, path+"["+strconv.Itoa(i)+"]", item, errs)
+			}
+		}
+	case map[string]any:
+		validateObject(s, in, path, v, errs)
+	}
+}
+
+func validateNumber(s *jsonschema.Schema, v float64, fail func(string, ...any)) {
+	if s.Minimum != nil && v < *s.Minimum {
+		fail("must be greater than or equal to %v", *s.Minimum)
+	}
+	if s.Maximum != nil && v > *s.Maximum {
+		fail("must be less than or equal to %v", *s.Maximum)
+	}
+	if s.ExclusiveMinimum != nil && v <= *s.ExclusiveMinimum {
+		fail("must be greater than %v", *s.ExclusiveMinimum)
+	}
+	if s.ExclusiveMaximum != nil && v >= *s.ExclusiveMaximum {
+		fail("must be less than %v", *s.ExclusiveMaximum)
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		if q := v / *s.MultipleOf; q != math.Trunc(q) {
+			fail("must be a multiple of %v", *s.MultipleOf)
+		}
+	}
+}
+
+func validateString(s *jsonschema.Schema, v string, fail func(string, ...any)) {
+	n := int64(utf8.RuneCountInString(v))
+	if s.MinLength > 0 && n < s.MinLength {
+		fail("must be at least %d characters long", s.MinLength)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		fail("must be at most %d characters long", *s.MaxLength)
+	}
+	if s.Pattern != nil {
+		if re := pattern(*s.Pattern); re != nil && !re.MatchString(v) {
+			fail("must match pattern %s", *s.Pattern)
+		}
+	}
+}
+
+func validateObject(s *jsonschema.Schema, in, path string, v map[string]any, errs *[]FieldError) {
+	for _, name := range s.Required {
+		if _, ok := v[name]; !ok {
+			*errs = append(*errs, FieldError{In: in, Field: join(path, name), Message: "is required"})
+		}
+	}
+
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := s.Properties[name]
+		switch {
+		case ok:
+			validate(prop.TypeObject, in, join(path, name), v[name], errs)
+		case s.AdditionalProperties != nil && s.AdditionalProperties.TypeBoolean != nil && !*s.AdditionalProperties.TypeBoolean:
+			*errs = append(*errs, FieldError{In: in, Field: join(path, name), Message: "is not allowed"})
+		case s.AdditionalProperties != nil:
+			validate(s.AdditionalProperties.TypeObject, in, join(path, name), v[name], errs)
+		}
+	}
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func pattern(expr string) *regexp.Regexp {
+	if re, ok := patterns.Load(expr); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		// An invalid pattern is a schema error, not a request error.
+		return nil
+	}
+	patterns.Store(expr, re)
+	return re
+}
+
+func matchesType(t *jsonschema.Type, v any) bool {
+	if t.SimpleTypes != nil {
+		return matchesSimpleType(*t.SimpleTypes, v)
+	}
+	if len(t.SliceOfSimpleTypeValues) == 0 {
+		return true
+	}
+	for _, st := range t.SliceOfSimpleTypeValues {
+		if matchesSimpleType(st, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesSimpleType(t jsonschema.SimpleType, v any) bool {
+	switch t {
+	case jsonschema.Null:
+		return v == nil
+	case jsonschema.Boolean:
+		_, ok := v.(bool)
+		return ok
+	case jsonschema.Integer:
+		n, ok := v.(float64)
+		return ok && n == math.Trunc(n)
+	case jsonschema.Number:
+		_, ok := v.(float64)
+		return ok
+	case jsonschema.String:
+		_, ok := v.(string)
+		return ok
+	case jsonschema.Array:
+		_, ok := v.([]any)
+		return ok
+	case jsonschema.Object:
+		_, ok := v.(map[string]any)
+		return ok
+	}
+	return true
+}
+
+func typeName(t *jsonschema.Type) string {
+	if t.SimpleTypes != nil {
+		return article(string(*t.SimpleTypes))
+	}
+	name := ""
+	for i, st := range t.SliceOfSimpleTypeValues {
+		if i > 0 {
+			name += " or "
+		}
+		name += article(string(st))
+	}
+	return name
+}
+
+func article(name string) string {
+	switch name {
+	case "null":
+		return name
+	case "array", "integer", "object":
+		return "an " + name
+	}
+	return "a " + name
+}
+
+func contains(values []any, v any) bool {
+	for _, value := range values {
+		if equal(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasArray(values []any) bool {
+	for _, value := range values {
+		if _, ok := jsonValue(value).([]any); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// equal compares a schema value, which may have any Go type, with a JSON value.
+func equal(schemaValue, v any) bool {
+	return reflect.DeepEqual(jsonValue(schemaValue), v)
+}
+
+func formatValues(values []any) string {
+	s := ""
+	for i, v := range values {
+		if i > 0 {
+			s += ", "
+		}
+		s += formatValue(v)
+	}
+	return "[" + s + "]"
+}
+
+func formatValue(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,133 @@
+package binding
+
+import (
+	"encoding"
+	"fmt"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+)
+
+// setValue decodes the raw parameter values into v.
+//
+// Slices take every value, each split on sep when it is not empty, see
+// separator. Other types take the first value.
+func setValue(v reflect.Value, raws []string, sep string) error {
+	if reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raws[0]))
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := setValue(elem.Elem(), raws, sep); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(raws[0]))
+			return nil
+		}
+		if sep != "" {
+			var items []string
+			for _, raw := range raws {
+				items = append(items, strings.Split(raw, sep)...)
+			}
+			raws = items
+		}
+		s := reflect.MakeSlice(v.Type(), len(raws), len(raws))
+		for i, raw := range raws {
+			if err := setValue(s.Index(i), []string{raw}, ""); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+
+	raw := raws[0]
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("must be a boolean")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be a non-negative integer")
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be a number")
+		}
+		v.SetFloat(n)
+	case reflect.Interface:
+		if !reflect.TypeOf(raw).AssignableTo(v.Type()) {
+			return fmt.Errorf("unsupported parameter type %s", v.Type())
+		}
+		v.Set(reflect.ValueOf(raw))
+	default:
+		return fmt.Errorf("unsupported parameter type %s", v.Type())
+	}
+	return nil
+}
+
+// separator returns the separator of the items of an array parameter, as
+// documented by the spec reflector: the collectionFormat tag sets the style
+// of the parameter, and otherwise query and formData arrays repeat the
+// parameter (form style, exploded) while header and path arrays separate
+// their items with commas (simple style).
+func separator(sf reflect.StructField, in string) string {
+	switch sf.Tag.Get("collectionFormat") {
+	case "csv":
+		return ","
+	case "ssv":
+		return " "
+	case "pipes":
+		return "|"
+	case "multi":
+		return ""
+	}
+	if in == InHeader || in == InPath {
+		return ","
+	}
+	return ""
+}
+
+// isFileField reports whether t holds uploaded files.
+func isFileField(t reflect.Type) bool {
+	return t == fileHeaderType || (t.Kind() == reflect.Slice && t.Elem() == fileHeaderType)
+}
+
+// setFiles sets the uploaded files to v, which must be a file field.
+func setFiles(v reflect.Value, files []*multipart.FileHeader) {
+	if v.Type() == fileHeaderType {
+		v.Set(reflect.ValueOf(files[0]))
+		return
+	}
+	v.Set(reflect.ValueOf(files))
+}
+
+// upload stands for uploaded files in the validated values,
+// their content is not validated.
+type upload struct{}
@@ -1,7 +1,12 @@
 package constant
 
 const (
-	OpenAPIFileName = "openapi.yaml"
+	OpenAPIFileName     = "openapi.yaml"
+	OpenAPIJSONFileName = "openapi.json"
+	OpenAPISpecName     = "openapi"
+
+	FormatYAML = "yaml"
+	FormatJSON = "json"
 
 	DefaultTitle       = "Fiber OpenAPI"
 	DefaultDescription = "OpenAPI documentation for Fiber applications"
@@ -1,81 +1,393 @@
 package handler
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"html"
+	"mime"
+	"net/http"
 	"path"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/oaswrap/fiberopenapi/internal/constant"
-	"github.com/oaswrap/spec"
 	"github.com/oaswrap/spec/openapi"
-	"github.com/swaggest/swgui"
-	"github.com/swaggest/swgui/v5cdn"
 )
 
+const (
+	contentTypeYAML = "application/x-yaml"
+	contentTypeJSON = "application/json"
+)
+
+// Generator marshals the OpenAPI schema served by the handler.
+//
+// If it also implements VersionedGenerator, the marshalled schema is cached
+// until the version changes, otherwise it is cached after the first success.
+type Generator interface {
+	MarshalYAML() ([]byte, error)
+	MarshalJSON() ([]byte, error)
+}
+
+// VersionedGenerator is a Generator that reports when its schema changes.
+type VersionedGenerator interface {
+	Generator
+	SpecVersion() uint64
+}
+
 type OpenAPIHandler struct {
-	cfg    *openapi.Config
-	gen    spec.Generator
-	once   sync.Once
-	err    error
-	schema []byte
+	cfg          *openapi.Config
+	gen          Generator
+	specFormat   string
+	ui           UI
+	docs         fiber.Handler
+	assets       fiber.Handler
+	assetPaths   []string
+	nonce        func(c *fiber.Ctx) string
+	cacheControl string
+	schemas      schemaCache
+	view         func(c *fiber.Ctx) (string, Generator)
+	viewsMu      sync.Mutex
+	views        map[string]*schemaCache
 }
 
-func NewOpenAPIHandler(cfg *openapi.Config, gen spec.Generator) *OpenAPIHandler {
-	return &OpenAPIHandler{
-		cfg: cfg,
-		gen: gen,
+// Option configures an OpenAPIHandler.
+type Option func(*OpenAPIHandler)
+
+// WithSpecFormat sets the format of the specification loaded by the docs UI
+// and served by OpenAPI when the Accept header has no preference.
+func WithSpecFormat(format string) Option {
+	return func(h *OpenAPIHandler) {
+		if format == constant.FormatJSON {
+			h.specFormat = constant.FormatJSON
+		}
 	}
 }
 
-func (h *OpenAPIHandler) OpenAPIYaml(c *fiber.Ctx) error {
-	h.once.Do(func() {
-		schema, err := h.gen.MarshalYAML()
+// WithUI sets the documentation UI served by Docs. Defaults to SwaggerUI.
+func WithUI(ui UI) Option {
+	return func(h *OpenAPIHandler) {
+		if ui != nil {
+			h.ui = ui
+		}
+	}
+}
+
+// WithCSPNonce sets the function returning the Content-Security-Policy nonce
+// of a request. The nonce is added to the inline and external scripts and
+// styles of the docs page, the policy itself is left to the application.
+func WithCSPNonce(nonce func(c *fiber.Ctx) string) Option {
+	return func(h *OpenAPIHandler) {
+		h.nonce = nonce
+	}
+}
+
+// WithCacheControl sets the Cache-Control header of the specification.
+// Defaults to "no-cache", clients revalidate the specification with its
+// ETag or Last-Modified date on every use.
+func WithCacheControl(cacheControl string) Option {
+	return func(h *OpenAPIHandler) {
+		if cacheControl != "" {
+			h.cacheControl = cacheControl
+		}
+	}
+}
+
+// WithView sets the function selecting the view of the specification served
+// to a request. It returns the key and the generator of the view, or an empty
+// key for the full specification. The schema of each view is cached under its
+// key, so views with the same key must serve the same schema.
+//
+// As the schema depends on the request, the Cache-Control header is made
+// private, so that shared caches do not serve the view of one client to another.
+func WithView(view func(c *fiber.Ctx) (string, Generator)) Option {
+	return func(h *OpenAPIHandler) {
+		h.view = view
+	}
+}
+
+// marshalledSchema is a marshalled schema with its validators.
+type marshalledSchema struct {
+	body     []byte
+	etag     string
+	modified time.Time
+}
+
+// schemaCache holds the schemas marshalled in each format.
+type schemaCache struct {
+	yaml cachedSchema
+	json cachedSchema
+}
+
+// cachedSchema holds the schema marshalled for a version of the generator.
+//
+// Failures are not cached, the next request marshals the schema again.
+type cachedSchema struct {
+	mu      sync.Mutex
+	valid   bool
+	version uint64
+	schema  marshalledSchema
+}
+
+func (c *cachedSchema) get(version uint64, marshal func() ([]byte, error)) (marshalledSchema, error) {
+	// The lock is held while marshalling so concurrent requests
+	// for a new version regenerate the schema only once.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.valid && c.version == version {
+		return c.schema, nil
+	}
+	body, err := marshal()
+	if err != nil {
+		return marshalledSchema{}, err
+	}
+	// A new version with the same content keeps its modification date.
+	if !c.valid || !bytes.Equal(c.schema.body, body) {
+		sum := sha256.Sum256(body)
+		c.schema = marshalledSchema{
+			body:     body,
+			etag:     `"` + hex.EncodeToString(sum[:16]) + `"`,
+			modified: time.Now().UTC().Truncate(time.Second),
+		}
+	}
+	c.valid, c.version = true, version
+
+	return c.schema, nil
+}
+
+func NewOpenAPIHandler(cfg *openapi.Config, gen Generator, opts ...Option) *OpenAPIHandler {
+	h := &OpenAPIHandler{
+		cfg:          cfg,
+		gen:          gen,
+		specFormat:   constant.FormatYAML,
+		ui:           SwaggerUI{},
+		cacheControl: "no-cache",
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.view != nil {
+		h.cacheControl = privateCacheControl(h.cacheControl)
+	}
+	uiCfg := h.uiConfig()
+	h.docs = h.ui.Handler(uiCfg)
+	if ui, ok := h.ui.(AssetServer); ok {
+		h.assets = ui.AssetsHandler(uiCfg)
+		h.assetPaths = ui.Assets()
+	}
+	return h
+}
+
+// OpenAPI serves the schema in the format requested by the Accept header,
+// falling back to the configured spec format when the header names neither
+// format, such as the header of a browser only accepting them through "*/*".
+func (h *OpenAPIHandler) OpenAPI(c *fiber.Ctx) error {
+	format := acceptedFormat(c.Get(fiber.HeaderAccept))
+	if format == "" {
+		format = h.specFormat
+	}
+
+	if format == constant.FormatJSON {
+		return h.OpenAPIJson(c)
+	}
+	return h.OpenAPIYaml(c)
+}
+
+// specMediaTypes are the media types of the schema formats.
+var specMediaTypes = map[string]string{
+	contentTypeJSON:    constant.FormatJSON,
+	contentTypeYAML:    constant.FormatYAML,
+	"application/yaml": constant.FormatYAML,
+	"text/yaml":        constant.FormatYAML,
+}
+
+// acceptedFormat returns the schema format the Accept header prefers among
+// the media types it names explicitly, empty if it names none. Wildcards are
+// ignored, the first of the media types with the highest quality wins.
+func acceptedFormat(accept string) string {
+	format, quality := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
 		if err != nil {
-			h.err = err
-			return
+			continue
+		}
+		f, ok := specMediaTypes[mediaType]
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > quality {
+			format, quality = f, q
 		}
-		h.schema = schema
-	})
-	if h.err != nil {
+	}
+	return format
+}
+
+func (h *OpenAPIHandler) OpenAPIYaml(c *fiber.Ctx) error {
+	gen, cache := h.generator(c)
+	schema, err := cache.yaml.get(version(gen), gen.MarshalYAML)
+	if err != nil {
 		return fiber.ErrInternalServerError
 	}
+	return h.sendSchema(c, contentTypeYAML, schema)
+}
 
-	// Set the response headers and content type.
-	// This ensures that the response is correctly formatted as YAML.
-	// The headers also prevent caching of the OpenAPI schema.
-	c.Set(fiber.HeaderContentType, "application/x-yaml")
-	c.Set(fiber.HeaderCacheControl, "no-cache, no-store, must-revalidate")
-	c.Set(fiber.HeaderPragma, "no-cache")
-	c.Set(fiber.HeaderExpires, "0")
+func (h *OpenAPIHandler) OpenAPIJson(c *fiber.Ctx) error {
+	gen, cache := h.generator(c)
+	schema, err := cache.json.get(version(gen), gen.MarshalJSON)
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+	return h.sendSchema(c, contentTypeJSON, schema)
+}
 
-	return c.Send(h.schema)
+// generator returns the generator of the schema served to the request, the
+// one of its view if any, and the cache of its schemas.
+func (h *OpenAPIHandler) generator(c *fiber.Ctx) (Generator, *schemaCache) {
+	if h.view == nil {
+		return h.gen, &h.schemas
+	}
+	key, gen := h.view(c)
+	if key == "" || gen == nil {
+		return h.gen, &h.schemas
+	}
+
+	h.viewsMu.Lock()
+	defer h.viewsMu.Unlock()
+	cache, ok := h.views[key]
+	if !ok {
+		if h.views == nil {
+			h.views = make(map[string]*schemaCache)
+		}
+		cache = &schemaCache{}
+		h.views[key] = cache
+	}
+	return gen, cache
+}
+
+// privateCacheControl returns the Cache-Control header with the private
+// directive in place of the directives allowing shared caches.
+func privateCacheControl(cacheControl string) string {
+	directives := []string{"private"}
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		name, _, _ := strings.Cut(strings.ToLower(directive), "=")
+		switch name {
+		case "", "public", "private", "s-maxage", "proxy-revalidate":
+			continue
+		}
+		directives = append(directives, directive)
+	}
+	return strings.Join(directives, ", ")
+}
+
+func version(gen Generator) uint64 {
+	if gen, ok := gen.(VersionedGenerator); ok {
+		return gen.SpecVersion()
+	}
+	return 0
+}
+
+// sendSchema sends the schema, or only its status when the validators of
+// the request show that the client already has it.
+func (h *OpenAPIHandler) sendSchema(c *fiber.Ctx, contentType string, schema marshalledSchema) error {
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderCacheControl, h.cacheControl)
+	c.Set(fiber.HeaderETag, schema.etag)
+	c.Set(fiber.HeaderLastModified, schema.modified.Format(http.TimeFormat))
+	c.Vary(fiber.HeaderAccept)
+
+	if notModified(c, schema) {
+		c.Status(fiber.StatusNotModified)
+		return nil
+	}
+	return c.Send(schema.body)
+}
+
+// notModified evaluates the conditional headers of a request for the schema,
+// If-Modified-Since is ignored when the request has an If-None-Match header.
+func notModified(c *fiber.Ctx, schema marshalledSchema) bool {
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		for _, etag := range strings.Split(noneMatch, ",") {
+			etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+			if etag == "*" || etag == schema.etag {
+				return true
+			}
+		}
+		return false
+	}
+	since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+	return err == nil && !schema.modified.After(since)
 }
 
 func (h *OpenAPIHandler) Docs(c *fiber.Ctx) error {
-	ui := v5cdn.NewHandlerWithConfig(h.swguiConfig())
-	return adaptor.HTTPHandler(ui)(c)
+	if err := h.docs(c); err != nil {
+		return err
+	}
+	if h.nonce == nil {
+		return nil
+	}
+	if nonce := h.nonce(c); nonce != "" {
+		// The page differs for every request.
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		c.Response().SetBodyRaw(addNonce(c.Response().Body(), nonce))
+	}
+	return nil
+}
+
+// Assets returns the paths of the assets served by DocsAssets, relative to
+// the docs path, or nil when the docs UI serves no assets.
+func (h *OpenAPIHandler) Assets() []string {
+	return h.assetPaths
 }
 
-func (h *OpenAPIHandler) swguiConfig() swgui.Config {
+// DocsAssets serves the assets of the docs UI under the docs path.
+func (h *OpenAPIHandler) DocsAssets(c *fiber.Ctx) error {
+	if h.assets == nil {
+		return fiber.ErrNotFound
+	}
+	return h.assets(c)
+}
+
+// addNonce adds the nonce attribute to the script, style and stylesheet link
+// elements of page.
+func addNonce(page []byte, nonce string) []byte {
+	attr := ` nonce="` + html.EscapeString(nonce) + `"`
+	for _, tag := range []string{"<script", "<style", `<link rel="stylesheet"`} {
+		page = bytes.ReplaceAll(page, []byte(tag), []byte(tag+attr))
+	}
+	return page
+}
+
+func (h *OpenAPIHandler) uiConfig() UIConfig {
 	cfg := h.cfg
-	openapiPath := path.Join(cfg.DocsPath, constant.OpenAPIFileName)
+	fileName := constant.OpenAPIFileName
+	if h.specFormat == constant.FormatJSON {
+		fileName = constant.OpenAPIJSONFileName
+	}
+	openapiPath := path.Join(cfg.DocsPath, fileName)
 	if cfg.BaseURL != "" {
-		if cfg.BaseURL[len(cfg.BaseURL)-1] != '/' {
-			openapiPath = "/" + openapiPath
-		}
-		openapiPath = cfg.BaseURL + openapiPath
-	}
-
-	return swgui.Config{
-		Title:              cfg.Title,
-		SwaggerJSON:        openapiPath,
-		BasePath:           cfg.DocsPath,
-		ShowTopBar:         cfg.SwaggerConfig.ShowTopBar,
-		HideCurl:           cfg.SwaggerConfig.HideCurl,
-		JsonEditor:         cfg.SwaggerConfig.JsonEditor,
-		PreAuthorizeApiKey: cfg.SwaggerConfig.PreAuthorizeApiKey,
-		SettingsUI:         cfg.SwaggerConfig.SettingsUI,
-		Proxy:              cfg.SwaggerConfig.Proxy,
+		openapiPath = strings.TrimSuffix(cfg.BaseURL, "/") + "/" + strings.TrimPrefix(openapiPath, "/")
+	}
+
+	swaggerCfg := openapi.SwaggerConfig{}
+	if cfg.SwaggerConfig != nil {
+		swaggerCfg = *cfg.SwaggerConfig
+	}
+
+	return UIConfig{
+		Title:    cfg.Title,
+		SpecURL:  openapiPath,
+		DocsPath: cfg.DocsPath,
+		Swagger:  swaggerCfg,
 	}
 }
@@ -2,8 +2,15 @@ package handler_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"net/http/httptest"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/oaswrap/fiberopenapi/internal/handler"
@@ -11,6 +18,7 @@ import (
 	"github.com/oaswrap/spec/openapi"
 	"github.com/oaswrap/spec/option"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewOpenAPIHandler(t *testing.T) {
@@ -61,6 +69,355 @@ func TestOpenAPIHandler_OpenAPIYaml(t *testing.T) {
 	})
 }
 
+type versionedGenerator struct {
+	version uint64
+	calls   int
+	err     error
+	yaml    []byte // Replaces the YAML schema when set.
+}
+
+func (g *versionedGenerator) MarshalYAML() ([]byte, error) {
+	g.calls++
+	if g.err != nil {
+		return nil, g.err
+	}
+	if g.yaml != nil {
+		return g.yaml, nil
+	}
+	return []byte(fmt.Sprintf("version: %d", g.version)), nil
+}
+
+func (g *versionedGenerator) MarshalJSON() ([]byte, error) {
+	g.calls++
+	if g.err != nil {
+		return nil, g.err
+	}
+	return []byte(fmt.Sprintf(`{"version": %d}`, g.version)), nil
+}
+
+func (g *versionedGenerator) SpecVersion() uint64 {
+	return g.version
+}
+
+func TestOpenAPIHandler_Cache(t *testing.T) {
+	get := func(t *testing.T, app *fiber.App) (int, string) {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest("GET", "/openapi.yaml", nil))
+		assert.NoError(t, err)
+		var body bytes.Buffer
+		_, err = body.ReadFrom(resp.Body)
+		assert.NoError(t, err)
+		return resp.StatusCode, body.String()
+	}
+
+	t.Run("regenerates when the version changes", func(t *testing.T) {
+		gen := &versionedGenerator{version: 1}
+		h := handler.NewOpenAPIHandler(spec.NewGenerator().Config(), gen)
+		app := fiber.New()
+		app.Get("/openapi.yaml", h.OpenAPIYaml)
+
+		_, body := get(t, app)
+		assert.Equal(t, "version: 1", body)
+		_, body = get(t, app)
+		assert.Equal(t, "version: 1", body)
+		assert.Equal(t, 1, gen.calls, "expected cached schema to be reused")
+
+		gen.version = 2
+		_, body = get(t, app)
+		assert.Equal(t, "version: 2", body)
+		assert.Equal(t, 2, gen.calls)
+	})
+	t.Run("does not cache errors", func(t *testing.T) {
+		gen := &versionedGenerator{version: 1, err: errors.New("boom")}
+		h := handler.NewOpenAPIHandler(spec.NewGenerator().Config(), gen)
+		app := fiber.New()
+		app.Get("/openapi.yaml", h.OpenAPIYaml)
+
+		status, _ := get(t, app)
+		assert.Equal(t, fiber.StatusInternalServerError, status)
+
+		gen.err = nil
+		status, body := get(t, app)
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, "version: 1", body)
+	})
+}
+
+func TestOpenAPIHandler_View(t *testing.T) {
+	full := &versionedGenerator{version: 1, yaml: []byte("paths: all")}
+	public := &versionedGenerator{version: 1, yaml: []byte("paths: public")}
+	h := handler.NewOpenAPIHandler(spec.NewGenerator().Config(), full,
+		handler.WithView(func(c *fiber.Ctx) (string, handler.Generator) {
+			if c.Get("X-Audience") == "internal" {
+				return "", nil
+			}
+			return "public", public
+		}),
+	)
+	app := fiber.New()
+	app.Get("/openapi.yaml", h.OpenAPIYaml)
+	var cacheControl string
+	get := func(t *testing.T, audience string) string {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/openapi.yaml", nil)
+		req.Header.Set("X-Audience", audience)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		cacheControl = resp.Header.Get(fiber.HeaderCacheControl)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	assert.Equal(t, "paths: public", get(t, "partner"))
+	assert.Equal(t, "paths: all", get(t, "internal"))
+	assert.Equal(t, "private, no-cache", cacheControl)
+	assert.Equal(t, "paths: public", get(t, "partner"))
+	assert.Equal(t, 1, public.calls, "expected the schema of the view to be cached")
+	assert.Equal(t, 1, full.calls)
+}
+
+func TestOpenAPIHandler_ViewCacheControl(t *testing.T) {
+	tests := []struct {
+		cacheControl string
+		want         string
+	}{
+		{"", "private, no-cache"},
+		{"public, max-age=600", "private, max-age=600"},
+		{"max-age=600, s-maxage=3600, must-revalidate", "private, max-age=600, must-revalidate"},
+		{"private, no-store", "private, no-store"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cacheControl, func(t *testing.T) {
+			gen := &versionedGenerator{version: 1}
+			h := handler.NewOpenAPIHandler(spec.NewGenerator().Config(), gen,
+				handler.WithCacheControl(tt.cacheControl),
+				handler.WithView(func(c *fiber.Ctx) (string, handler.Generator) { return "", nil }),
+			)
+			app := fiber.New()
+			app.Get("/openapi.yaml", h.OpenAPIYaml)
+			resp, err := app.Test(httptest.NewRequest("GET", "/openapi.yaml", nil))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resp.Header.Get(fiber.HeaderCacheControl))
+		})
+	}
+}
+
+func TestOpenAPIHandler_ConditionalGet(t *testing.T) {
+	gen := &versionedGenerator{version: 1, yaml: []byte("openapi: 3.0.3")}
+	h := handler.NewOpenAPIHandler(spec.NewGenerator().Config(), gen)
+	app := fiber.New()
+	app.Get("/openapi.yaml", h.OpenAPIYaml)
+	get := func(t *testing.T, header map[string]string) *http.Response {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/openapi.yaml", nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := get(t, nil)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "no-cache", resp.Header.Get(fiber.HeaderCacheControl))
+	etag := resp.Header.Get(fiber.HeaderETag)
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag, "expected a strong ETag")
+	lastModified := resp.Header.Get(fiber.HeaderLastModified)
+	modified, err := http.ParseTime(lastModified)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		status int
+	}{
+		{"matching etag", map[string]string{fiber.HeaderIfNoneMatch: etag}, fiber.StatusNotModified},
+		{"matching weak etag in a list", map[string]string{fiber.HeaderIfNoneMatch: `"other", W/` + etag}, fiber.StatusNotModified},
+		{"any etag", map[string]string{fiber.HeaderIfNoneMatch: "*"}, fiber.StatusNotModified},
+		{"other etag", map[string]string{fiber.HeaderIfNoneMatch: `"other"`}, fiber.StatusOK},
+		{"not modified since", map[string]string{fiber.HeaderIfModifiedSince: lastModified}, fiber.StatusNotModified},
+		{"modified since", map[string]string{fiber.HeaderIfModifiedSince: modified.Add(-time.Second).Format(http.TimeFormat)}, fiber.StatusOK},
+		{"etag takes precedence", map[string]string{
+			fiber.HeaderIfNoneMatch:     `"other"`,
+			fiber.HeaderIfModifiedSince: lastModified,
+		}, fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := get(t, tt.header)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, etag, resp.Header.Get(fiber.HeaderETag))
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			if tt.status == fiber.StatusNotModified {
+				assert.Empty(t, body)
+			} else {
+				assert.Equal(t, "openapi: 3.0.3", string(body))
+			}
+		})
+	}
+
+	t.Run("new version with the same content", func(t *testing.T) {
+		gen.version = 2
+		resp := get(t, map[string]string{fiber.HeaderIfNoneMatch: etag})
+		assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+		assert.Equal(t, 2, gen.calls, "expected the schema to be marshalled again")
+	})
+	t.Run("new content", func(t *testing.T) {
+		gen.version, gen.yaml = 3, []byte("openapi: 3.1.0")
+		resp := get(t, map[string]string{fiber.HeaderIfNoneMatch: etag})
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.NotEqual(t, etag, resp.Header.Get(fiber.HeaderETag))
+	})
+	t.Run("custom cache control", func(t *testing.T) {
+		h := handler.NewOpenAPIHandler(spec.NewGenerator().Config(), gen, handler.WithCacheControl("public, max-age=60"))
+		app := fiber.New()
+		app.Get("/openapi.yaml", h.OpenAPIYaml)
+		resp, err := app.Test(httptest.NewRequest("GET", "/openapi.yaml", nil))
+		require.NoError(t, err)
+		assert.Equal(t, "public, max-age=60", resp.Header.Get(fiber.HeaderCacheControl))
+	})
+}
+
+func TestOpenAPIHandler_OpenAPIJson(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		generator := spec.NewGenerator()
+		cfg := generator.Config()
+
+		h := handler.NewOpenAPIHandler(cfg, generator)
+		app := fiber.New()
+		app.Get("/openapi.json", h.OpenAPIJson)
+
+		req := httptest.NewRequest("GET", "/openapi.json", nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.NotNil(t, resp)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get(fiber.HeaderContentType))
+
+		var body bytes.Buffer
+		_, err = body.ReadFrom(resp.Body)
+		assert.NoError(t, err)
+		assert.True(t, json.Valid(body.Bytes()), "expected valid JSON body")
+	})
+	t.Run("error", func(t *testing.T) {
+		r := spec.NewGenerator()
+		cfg := r.Config()
+
+		r.Get("/user/{id}", option.Summary("Get User by ID"))
+		h := handler.NewOpenAPIHandler(cfg, r)
+
+		app := fiber.New()
+		app.Get("/openapi.json", h.OpenAPIJson)
+		req := httptest.NewRequest("GET", "/openapi.json", nil)
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+}
+
+func TestOpenAPIHandler_OpenAPI(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []handler.Option
+		accept   string
+		expected string
+	}{
+		{
+			name:     "default format without accept header",
+			expected: "application/x-yaml",
+		},
+		{
+			name:     "configured format without accept header",
+			opts:     []handler.Option{handler.WithSpecFormat("json")},
+			expected: "application/json",
+		},
+		{
+			name:     "configured format with wildcard accept header",
+			opts:     []handler.Option{handler.WithSpecFormat("json")},
+			accept:   "*/*",
+			expected: "application/json",
+		},
+		{
+			name:     "accept json",
+			accept:   "application/json",
+			expected: "application/json",
+		},
+		{
+			name:     "accept yaml",
+			opts:     []handler.Option{handler.WithSpecFormat("json")},
+			accept:   "application/yaml",
+			expected: "application/x-yaml",
+		},
+		{
+			name:     "accept text yaml",
+			opts:     []handler.Option{handler.WithSpecFormat("json")},
+			accept:   "text/yaml",
+			expected: "application/x-yaml",
+		},
+		{
+			name:     "accept with quality values",
+			accept:   "application/x-yaml;q=0.5, application/json",
+			expected: "application/json",
+		},
+		{
+			name:     "unsupported accept header",
+			opts:     []handler.Option{handler.WithSpecFormat("json")},
+			accept:   "text/html",
+			expected: "application/json",
+		},
+		{
+			name:     "browser accept header",
+			accept:   "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			expected: "application/x-yaml",
+		},
+		{
+			name:     "browser accept header with json spec format",
+			opts:     []handler.Option{handler.WithSpecFormat("json")},
+			accept:   "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			expected: "application/json",
+		},
+		{
+			name:     "wildcard subtype",
+			accept:   "application/*",
+			expected: "application/x-yaml",
+		},
+		{
+			name:     "refused format",
+			opts:     []handler.Option{handler.WithSpecFormat("json")},
+			accept:   "application/json;q=0, text/yaml;q=0.1",
+			expected: "application/x-yaml",
+		},
+		{
+			name:     "unknown spec format",
+			opts:     []handler.Option{handler.WithSpecFormat("xml")},
+			expected: "application/x-yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := spec.NewGenerator()
+			h := handler.NewOpenAPIHandler(generator.Config(), generator, tt.opts...)
+			app := fiber.New()
+			app.Get("/openapi", h.OpenAPI)
+
+			req := httptest.NewRequest("GET", "/openapi", nil)
+			if tt.accept != "" {
+				req.Header.Set(fiber.HeaderAccept, tt.accept)
+			}
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+			assert.Equal(t, tt.expected, resp.Header.Get(fiber.HeaderContentType))
+			assert.Equal(t, fiber.HeaderAccept, resp.Header.Get(fiber.HeaderVary))
+		})
+	}
+}
+
 func TestOpenAPIHandler_Docs(t *testing.T) {
 	generator := spec.NewGenerator(option.WithSwaggerConfig(openapi.SwaggerConfig{}))
 	cfg := generator.Config()
@@ -97,4 +454,162 @@ func TestOpenAPIHandler_Docs(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, bytes.String(), "Swagger UI")
 	assert.Contains(t, bytes.String(), "http://localhost:3000/openapi.yaml")
+
+	generator = spec.NewGenerator(
+		option.WithDocsPath("/docs"),
+		option.WithSwaggerConfig(openapi.SwaggerConfig{}),
+	)
+	h = handler.NewOpenAPIHandler(generator.Config(), generator, handler.WithSpecFormat("json"))
+	app = fiber.New()
+	app.Get("/docs", h.Docs)
+	req = httptest.NewRequest("GET", "/docs", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	bytes.Reset()
+	_, err = bytes.ReadFrom(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, bytes.String(), "/docs/openapi.json")
+}
+
+func TestOpenAPIHandler_DocsUI(t *testing.T) {
+	tests := []struct {
+		name   string
+		ui     handler.UI
+		marker string
+	}{
+		{name: "swagger ui", ui: handler.SwaggerUI{}, marker: "swagger-ui"},
+		{name: "embedded swagger ui", ui: handler.SwaggerUIEmbedded{}, marker: `src="/docs/swagger-ui-bundle.js"`},
+		{name: "redoc", ui: handler.Redoc{}, marker: `<redoc spec-url="http://localhost:3000/docs/openapi.json">`},
+		{name: "scalar", ui: handler.Scalar{}, marker: `data-url="http://localhost:3000/docs/openapi.json"`},
+		{name: "rapidoc", ui: handler.RapiDoc{}, marker: `<rapi-doc spec-url="http://localhost:3000/docs/openapi.json"`},
+		{name: "elements", ui: handler.Elements{}, marker: `apiDescriptionUrl="http://localhost:3000/docs/openapi.json"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := spec.NewGenerator(
+				option.WithTitle("Pet <Store>"),
+				option.WithBaseURL("http://localhost:3000"),
+				option.WithDocsPath("/docs"),
+				option.WithSwaggerConfig(openapi.SwaggerConfig{}),
+			)
+			h := handler.NewOpenAPIHandler(generator.Config(), generator,
+				handler.WithSpecFormat("json"),
+				handler.WithUI(tt.ui),
+			)
+			app := fiber.New()
+			app.Get("/docs", h.Docs)
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/docs", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+			assert.Contains(t, resp.Header.Get(fiber.HeaderContentType), "text/html")
+
+			var body bytes.Buffer
+			_, err = body.ReadFrom(resp.Body)
+			assert.NoError(t, err)
+			assert.Contains(t, body.String(), tt.marker)
+			assert.Contains(t, body.String(), "Pet &lt;Store&gt;", "expected the escaped title")
+			for _, ref := range regexp.MustCompile(`(?:src|href)="(https://[^"]*\.(?:js|css))"`).FindAllStringSubmatch(body.String(), -1) {
+				assert.Regexp(t, `[@/]v?\d+\.\d+\.\d+/`, ref[1], "expected the CDN assets to be pinned")
+			}
+		})
+	}
+}
+
+func TestOpenAPIHandler_DocsAssets(t *testing.T) {
+	generator := spec.NewGenerator(
+		option.WithDocsPath("/docs"),
+		option.WithSwaggerConfig(openapi.SwaggerConfig{}),
+	)
+	h := handler.NewOpenAPIHandler(generator.Config(), generator)
+	assert.Empty(t, h.Assets(), "expected the CDN UI to serve no assets")
+
+	h = handler.NewOpenAPIHandler(generator.Config(), generator, handler.WithUI(handler.SwaggerUIEmbedded{}))
+	require.Contains(t, h.Assets(), "swagger-ui-bundle.js", "expected the embedded UI to serve its assets")
+	app := fiber.New()
+	app.Get("/docs/*", h.DocsAssets)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/docs/swagger-ui-bundle.js", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "public, max-age=86400", resp.Header.Get(fiber.HeaderCacheControl))
+	etag := resp.Header.Get(fiber.HeaderETag)
+	assert.NotEmpty(t, etag)
+
+	req := httptest.NewRequest("GET", "/docs/swagger-ui-bundle.js", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, etag)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/docs/missing.js", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(fiber.HeaderCacheControl))
+
+	app.Get("/docs/changelog", func(c *fiber.Ctx) error {
+		return c.SendString("changelog")
+	})
+	resp, err = app.Test(httptest.NewRequest("GET", "/docs/changelog", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "changelog", string(body), "expected the assets not to shadow the later routes")
+}
+
+func TestOpenAPIHandler_DocsCSPNonce(t *testing.T) {
+	generator := spec.NewGenerator(
+		option.WithDocsPath("/docs"),
+		option.WithSwaggerConfig(openapi.SwaggerConfig{}),
+	)
+	h := handler.NewOpenAPIHandler(generator.Config(), generator,
+		handler.WithUI(handler.SwaggerUIEmbedded{}),
+		handler.WithCSPNonce(func(c *fiber.Ctx) string { return c.Get("X-Nonce") }),
+	)
+	app := fiber.New()
+	app.Get("/docs", h.Docs)
+
+	req := httptest.NewRequest("GET", "/docs", nil)
+	req.Header.Set("X-Nonce", `r4nd"m`)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "no-store", resp.Header.Get(fiber.HeaderCacheControl))
+
+	var body bytes.Buffer
+	_, err = body.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, body.String(), `<script nonce="r4nd&#34;m" src="/docs/swagger-ui-bundle.js">`)
+	assert.Contains(t, body.String(), `<style nonce="r4nd&#34;m">`)
+	assert.NotContains(t, body.String(), "<script>", "expected every script to carry the nonce")
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/docs", nil))
+	require.NoError(t, err)
+	body.Reset()
+	_, err = body.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	assert.NotContains(t, body.String(), "nonce=", "expected no nonce without one for the request")
+}
+
+func TestOpenAPIHandler_DocsCSPNonceStylesheet(t *testing.T) {
+	generator := spec.NewGenerator(
+		option.WithDocsPath("/docs"),
+		option.WithSwaggerConfig(openapi.SwaggerConfig{}),
+	)
+	h := handler.NewOpenAPIHandler(generator.Config(), generator,
+		handler.WithUI(handler.Elements{}),
+		handler.WithCSPNonce(func(c *fiber.Ctx) string { return "abc" }),
+	)
+	app := fiber.New()
+	app.Get("/docs", h.Docs)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/docs", nil))
+	require.NoError(t, err)
+	var body bytes.Buffer
+	_, err = body.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	assert.Regexp(t, `<script nonce="abc" src="[^"]+/web-components\.min\.js"`, body.String())
+	assert.Regexp(t, `<link rel="stylesheet" nonce="abc" href="[^"]+/styles\.min\.css"`, body.String())
 }
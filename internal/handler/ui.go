@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"bytes"
+	"html/template"
+	"io/fs"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/oaswrap/spec/openapi"
+	"github.com/swaggest/swgui"
+	"github.com/swaggest/swgui/v5/static"
+	"github.com/swaggest/swgui/v5cdn"
+	"github.com/swaggest/swgui/v5emb"
+)
+
+// UIConfig holds the settings shared by the documentation UIs.
+type UIConfig struct {
+	// Title is the title of the documentation page.
+	Title string
+	// SpecURL is the URL of the specification loaded by the UI.
+	SpecURL string
+	// DocsPath is the path the UI is served at.
+	DocsPath string
+	// Swagger holds the settings specific to Swagger UI.
+	Swagger openapi.SwaggerConfig
+}
+
+// UI renders a documentation UI.
+type UI interface {
+	// Handler returns the handler serving the UI.
+	Handler(cfg UIConfig) fiber.Handler
+}
+
+// AssetServer is a UI serving its own assets under the docs path.
+type AssetServer interface {
+	UI
+	// AssetsHandler returns the handler serving the assets.
+	AssetsHandler(cfg UIConfig) fiber.Handler
+	// Assets returns the paths of the assets, relative to the docs path.
+	Assets() []string
+}
+
+// SwaggerUI serves Swagger UI from a CDN.
+type SwaggerUI struct{}
+
+// Handler returns the handler serving Swagger UI.
+func (SwaggerUI) Handler(cfg UIConfig) fiber.Handler {
+	return adaptor.HTTPHandler(v5cdn.NewHandlerWithConfig(swaggerConfig(cfg)))
+}
+
+// SwaggerUIEmbedded serves Swagger UI with the assets embedded in the binary,
+// so the docs page loads nothing from outside the application.
+type SwaggerUIEmbedded struct{}
+
+// Handler returns the handler serving the Swagger UI page.
+func (SwaggerUIEmbedded) Handler(cfg UIConfig) fiber.Handler {
+	return adaptor.HTTPHandler(v5emb.NewHandlerWithConfig(swaggerConfig(cfg)))
+}
+
+// AssetsHandler returns the handler serving the Swagger UI assets.
+//
+// The assets are revalidated with their ETag once a day. Requests for other
+// paths under the docs path are passed to the next handler, so that the
+// assets do not shadow the routes registered after them.
+func (ui SwaggerUIEmbedded) AssetsHandler(cfg UIConfig) fiber.Handler {
+	h := adaptor.HTTPHandler(v5emb.NewHandlerWithConfig(swaggerConfig(cfg)))
+	assets := make(map[string]bool)
+	for _, name := range ui.Assets() {
+		assets[name] = true
+	}
+	prefix := strings.TrimSuffix(cfg.DocsPath, "/") + "/"
+	return func(c *fiber.Ctx) error {
+		if !assets[strings.TrimPrefix(c.Path(), prefix)] {
+			return c.Next()
+		}
+		if err := h(c); err != nil {
+			return err
+		}
+		if c.Response().StatusCode() == fiber.StatusOK {
+			c.Set(fiber.HeaderCacheControl, "public, max-age=86400")
+		}
+		return nil
+	}
+}
+
+// Assets returns the paths of the Swagger UI assets, relative to the docs path.
+func (SwaggerUIEmbedded) Assets() []string {
+	var assets []string
+	entries, _ := fs.ReadDir(static.FS, ".")
+	for _, entry := range entries {
+		// Compressed assets are served under their uncompressed name.
+		assets = append(assets, strings.TrimSuffix(entry.Name(), ".gz"))
+	}
+	return assets
+}
+
+func swaggerConfig(cfg UIConfig) swgui.Config {
+	return swgui.Config{
+		Title:              cfg.Title,
+		SwaggerJSON:        cfg.SpecURL,
+		BasePath:           cfg.DocsPath,
+		ShowTopBar:         cfg.Swagger.ShowTopBar,
+		HideCurl:           cfg.Swagger.HideCurl,
+		JsonEditor:         cfg.Swagger.JsonEditor,
+		PreAuthorizeApiKey: cfg.Swagger.PreAuthorizeApiKey,
+		SettingsUI:         cfg.Swagger.SettingsUI,
+		Proxy:              cfg.Swagger.Proxy,
+	}
+}
+
+// Redoc serves Redoc from a CDN.
+type Redoc struct{}
+
+// Handler returns the handler serving Redoc.
+func (Redoc) Handler(cfg UIConfig) fiber.Handler {
+	return pageHandler(cfg, redocPage)
+}
+
+// Scalar serves the Scalar API reference from a CDN.
+type Scalar struct{}
+
+// Handler returns the handler serving Scalar.
+func (Scalar) Handler(cfg UIConfig) fiber.Handler {
+	return pageHandler(cfg, scalarPage)
+}
+
+// RapiDoc serves RapiDoc from a CDN.
+type RapiDoc struct{}
+
+// Handler returns the handler serving RapiDoc.
+func (RapiDoc) Handler(cfg UIConfig) fiber.Handler {
+	return pageHandler(cfg, rapiDocPage)
+}
+
+// Elements serves Stoplight Elements from a CDN.
+type Elements struct{}
+
+// Handler returns the handler serving Stoplight Elements.
+func (Elements) Handler(cfg UIConfig) fiber.Handler {
+	return pageHandler(cfg, elementsPage)
+}
+
+const pageLayout = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{ .Title }}</title>
+  <style>body { margin: 0; padding: 0; }</style>
+  {{ template "head" . }}
+</head>
+<body>
+  {{ template "body" . }}
+</body>
+</html>
+`
+
+// The CDN bundles are pinned to exact versions, so that the docs page does
+// not change with a new release of a UI.
+const (
+	redocScript    = "https://cdn.jsdelivr.net/npm/redoc@2.5.0/bundles/redoc.standalone.js"
+	scalarScript   = "https://cdn.jsdelivr.net/npm/@scalar/api-reference@1.28.0/dist/browser/standalone.js"
+	rapiDocScript  = "https://cdn.jsdelivr.net/npm/rapidoc@9.3.8/dist/rapidoc-min.js"
+	elementsScript = "https://cdn.jsdelivr.net/npm/@stoplight/elements@8.0.0/web-components.min.js"
+	elementsStyle  = "https://cdn.jsdelivr.net/npm/@stoplight/elements@8.0.0/styles.min.css"
+)
+
+var (
+	redocPage = page(`
+{{ define "head" }}{{ end }}
+{{ define "body" }}
+<redoc spec-url="{{ .SpecURL }}"></redoc>
+<script src="` + redocScript + `" crossorigin="anonymous"></script>
+{{ end }}`)
+
+	scalarPage = page(`
+{{ define "head" }}{{ end }}
+{{ define "body" }}
+<script id="api-reference" data-url="{{ .SpecURL }}"></script>
+<script src="` + scalarScript + `" crossorigin="anonymous"></script>
+{{ end }}`)
+
+	rapiDocPage = page(`
+{{ define "head" }}<script type="module" src="` + rapiDocScript + `" crossorigin="anonymous"></script>{{ end }}
+{{ define "body" }}
+<rapi-doc spec-url="{{ .SpecURL }}" render-style="read" show-header="false"></rapi-doc>
+{{ end }}`)
+
+	elementsPage = page(`
+{{ define "head" }}
+<script src="` + elementsScript + `" crossorigin="anonymous"></script>
+<link rel="stylesheet" href="` + elementsStyle + `" crossorigin="anonymous">
+{{ end }}
+{{ define "body" }}
+<elements-api apiDescriptionUrl="{{ .SpecURL }}" router="hash" layout="sidebar"></elements-api>
+{{ end }}`)
+)
+
+func page(content string) *template.Template {
+	return template.Must(template.Must(template.New("page").Parse(pageLayout)).Parse(content))
+}
+
+// pageHandler renders the page once and serves it.
+func pageHandler(cfg UIConfig, tmpl *template.Template) fiber.Handler {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, cfg)
+	return func(c *fiber.Ctx) error {
+		if err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.Send(buf.Bytes())
+	}
+}
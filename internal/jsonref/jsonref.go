@@ -0,0 +1,25 @@
+// Package jsonref resolves the local references of a decoded OpenAPI document.
+package jsonref
+
+import "strings"
+
+// Resolve returns the object a local reference such as
+// "#/components/schemas/Pet" points to in doc, nil if there is none.
+func Resolve(doc map[string]any, ref string) map[string]any {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+	var target any = doc
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		m, _ := target.(map[string]any)
+		target = m[Unescape(token)]
+	}
+	obj, _ := target.(map[string]any)
+	return obj
+}
+
+// Unescape decodes a token of a reference, in which "~1" stands for "/" and
+// "~0" for "~".
+func Unescape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
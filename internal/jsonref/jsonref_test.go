@@ -0,0 +1,23 @@
+package jsonref
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	pet := map[string]any{"type": "object"}
+	route := map[string]any{"type": "string"}
+	doc := map[string]any{
+		"components": map[string]any{
+			"schemas": map[string]any{"Pet": pet, "a/b~c": route},
+		},
+	}
+
+	assert.Equal(t, pet, Resolve(doc, "#/components/schemas/Pet"))
+	assert.Equal(t, route, Resolve(doc, "#/components/schemas/a~1b~0c"))
+	assert.Nil(t, Resolve(doc, "#/components/schemas/Missing"))
+	assert.Nil(t, Resolve(doc, "#/components/schemas/Pet/type"), "expected a reference to a value that is not an object to resolve to nil")
+	assert.Nil(t, Resolve(doc, "other.yaml#/components/schemas/Pet"), "expected external references not to be followed")
+}
@@ -0,0 +1,179 @@
+// Package sample builds sample values from the JSON schemas of an OpenAPI
+// document, for mock responses and examples.
+package sample
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/oaswrap/fiberopenapi/internal/jsonref"
+)
+
+// maxDepth bounds the nesting of the sample values of recursive schemas.
+const maxDepth = 8
+
+// Value returns a sample value of the schema, whose references are resolved
+// in the components of doc.
+//
+// The example, default, const or first enum value of a schema is used when
+// there is one, objects have a sample of every property, arrays a single
+// item, and the other values are derived from the type and format.
+func Value(doc map[string]any, schema any) any {
+	g := &generator{doc: doc, refs: make(map[string]int)}
+	return g.value(schema, 0)
+}
+
+type generator struct {
+	doc  map[string]any
+	refs map[string]int // references being sampled, to stop recursion
+}
+
+func (g *generator) value(v any, depth int) any {
+	schema, _ := v.(map[string]any)
+	if schema == nil || depth > maxDepth {
+		return nil
+	}
+	if ref, _ := schema["$ref"].(string); ref != "" {
+		if g.refs[ref] > 0 {
+			return nil
+		}
+		g.refs[ref]++
+		defer func() { g.refs[ref]-- }()
+		return g.value(jsonref.Resolve(g.doc, ref), depth+1)
+	}
+
+	for _, key := range []string{"example", "default", "const"} {
+		if example, ok := schema[key]; ok {
+			return example
+		}
+	}
+	if examples, ok := schema["examples"].([]any); ok && len(examples) > 0 {
+		return examples[0]
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	if all, ok := schema["allOf"].([]any); ok && len(all) > 0 {
+		merged := make(map[string]any)
+		for _, s := range all {
+			if obj, ok := g.value(s, depth+1).(map[string]any); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		if obj, ok := g.object(schema, depth).(map[string]any); ok {
+			for k, v := range obj {
+				merged[k] = v
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if list, ok := schema[key].([]any); ok && len(list) > 0 {
+			return g.value(list[0], depth+1)
+		}
+	}
+
+	switch Type(schema) {
+	case "object":
+		return g.object(schema, depth)
+	case "array":
+		if items, ok := schema["items"]; ok {
+			return []any{g.value(items, depth+1)}
+		}
+		return []any{}
+	case "string":
+		return stringValue(schema)
+	case "integer":
+		return int64(number(schema))
+	case "number":
+		return number(schema)
+	case "boolean":
+		return true
+	case "null":
+		return nil
+	}
+	if _, ok := schema["properties"]; ok {
+		return g.object(schema, depth)
+	}
+	return nil
+}
+
+// object returns a sample object with a value for every property.
+func (g *generator) object(schema map[string]any, depth int) any {
+	obj := make(map[string]any)
+	props, _ := schema["properties"].(map[string]any)
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		obj[name] = g.value(props[name], depth+1)
+	}
+	if additional, ok := schema["additionalProperties"].(map[string]any); ok && len(props) == 0 {
+		obj["key"] = g.value(additional, depth+1)
+	}
+	return obj
+}
+
+// Type returns the type of a schema, the first type other than "null" for
+// the list of types of OpenAPI 3.1.
+func Type(schema map[string]any) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, e := range t {
+			if e, ok := e.(string); ok && e != "null" {
+				return e
+			}
+		}
+	}
+	return ""
+}
+
+// formats are the sample values of the string formats.
+var formats = map[string]string{
+	"date-time": "2024-01-02T15:04:05Z",
+	"date":      "2024-01-02",
+	"time":      "15:04:05",
+	"email":     "user@example.com",
+	"uri":       "https://example.com",
+	"url":       "https://example.com",
+	"hostname":  "example.com",
+	"uuid":      "123e4567-e89b-12d3-a456-426614174000",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"byte":      "c3RyaW5n",
+	"binary":    "string",
+	"duration":  "1s",
+}
+
+func stringValue(schema map[string]any) string {
+	format, _ := schema["format"].(string)
+	if v, ok := formats[format]; ok {
+		return v
+	}
+	v := "string"
+	if minLength, ok := schema["minLength"].(float64); ok && int(minLength) > len(v) {
+		v += strings.Repeat("s", int(minLength)-len(v))
+	}
+	if maxLength, ok := schema["maxLength"].(float64); ok && int(maxLength) < len(v) {
+		v = v[:int(maxLength)]
+	}
+	return v
+}
+
+// number returns 0, or the closest value within the minimum and maximum.
+func number(schema map[string]any) float64 {
+	if minimum, ok := schema["minimum"].(float64); ok && minimum > 0 {
+		return minimum
+	}
+	if maximum, ok := schema["maximum"].(float64); ok && maximum < 0 {
+		return maximum
+	}
+	return 0
+}
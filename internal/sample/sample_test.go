@@ -0,0 +1,95 @@
+package sample_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/oaswrap/fiberopenapi/internal/sample"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValue(t *testing.T) {
+	doc := map[string]any{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"properties": {
+						"id": {"type": "integer", "example": 42},
+						"name": {"type": "string", "minLength": 8},
+						"status": {"type": "string", "enum": ["available", "sold"]},
+						"owner": {"$ref": "#/components/schemas/Owner"},
+						"parent": {"$ref": "#/components/schemas/Pet"},
+						"tags": {"type": "array", "items": {"type": "string", "format": "uuid"}}
+					}
+				},
+				"Owner": {
+					"allOf": [
+						{"type": "object", "properties": {"email": {"type": "string", "format": "email"}}},
+						{"type": "object", "properties": {"age": {"type": "integer", "minimum": 18}}}
+					]
+				}
+			}
+		}
+	}`), &doc))
+
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{
+			name:   "reference",
+			schema: `{"$ref": "#/components/schemas/Pet"}`,
+			want: `{
+				"id": 42,
+				"name": "stringss",
+				"status": "available",
+				"owner": {"age": 18, "email": "user@example.com"},
+				"parent": null,
+				"tags": ["123e4567-e89b-12d3-a456-426614174000"]
+			}`,
+		},
+		{
+			name:   "nullable types",
+			schema: `{"type": ["null", "number"], "maximum": -1}`,
+			want:   `-1`,
+		},
+		{
+			name:   "default",
+			schema: `{"type": "boolean", "default": false}`,
+			want:   `false`,
+		},
+		{
+			name:   "one of",
+			schema: `{"oneOf": [{"type": "string", "format": "date"}, {"type": "integer"}]}`,
+			want:   `"2024-01-02"`,
+		},
+		{
+			name:   "map",
+			schema: `{"type": "object", "additionalProperties": {"type": "integer"}}`,
+			want:   `{"key": 0}`,
+		},
+		{
+			name:   "examples",
+			schema: `{"type": "string", "examples": ["first", "second"]}`,
+			want:   `"first"`,
+		},
+		{
+			name:   "unknown reference",
+			schema: `{"$ref": "#/components/schemas/Unknown"}`,
+			want:   `null`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema any
+			require.NoError(t, json.Unmarshal([]byte(tt.schema), &schema))
+			got, err := json.Marshal(sample.Value(doc, schema))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
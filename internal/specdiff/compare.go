@@ -0,0 +1,294 @@
+package specdiff
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/oaswrap/fiberopenapi/internal/jsonref"
+)
+
+// comparer compares an operation of the baseline with the current one.
+type comparer struct {
+	operation         string
+	baseline, current map[string]any
+	changes           []Change
+
+	seen map[[2]string]bool // pairs of schema references compared
+}
+
+func (c *comparer) add(breaking bool, format string, args ...any) {
+	c.changes = append(c.changes, Change{
+		Operation: c.operation,
+		Message:   fmt.Sprintf(format, args...),
+		Breaking:  breaking,
+	})
+}
+
+func (c *comparer) compareOperation(base, cur operation) {
+	c.compareParameters(base, cur)
+	c.compareRequestBody(base.op["requestBody"], cur.op["requestBody"])
+	c.compareResponses(base.op["responses"], cur.op["responses"])
+}
+
+// compareParameters compares the parameters of the operations. A new
+// required parameter, or a parameter that becomes required, is breaking.
+func (c *comparer) compareParameters(base, cur operation) {
+	baseParams := parameters(c.baseline, base)
+	curParams := parameters(c.current, cur)
+
+	for _, key := range sortedKeys(baseParams) {
+		if _, ok := curParams[key]; !ok {
+			c.add(false, "parameter %s removed", key)
+		}
+	}
+	for _, key := range sortedKeys(curParams) {
+		curParam := curParams[key]
+		baseParam, ok := baseParams[key]
+		switch {
+		case !ok && isTrue(curParam["required"]):
+			c.add(true, "required parameter %s added", key)
+		case !ok:
+			c.add(false, "optional parameter %s added", key)
+		default:
+			if !isTrue(baseParam["required"]) && isTrue(curParam["required"]) {
+				c.add(true, "parameter %s became required", key)
+			}
+			c.compareSchema(baseParam["schema"], curParam["schema"], "parameter "+key, true)
+		}
+	}
+}
+
+// parameters returns the parameters of the operation by location and name,
+// the operation parameters overriding the path item parameters.
+func parameters(doc map[string]any, op operation) map[string]map[string]any {
+	params := make(map[string]map[string]any)
+	opParams, _ := op.op["parameters"].([]any)
+	for _, list := range [][]any{op.params, opParams} {
+		for _, p := range list {
+			param, _ := resolve(doc, p)
+			if param == nil {
+				continue
+			}
+			in, _ := param["in"].(string)
+			name, _ := param["name"].(string)
+			params[in+" "+name] = param
+		}
+	}
+	return params
+}
+
+// compareRequestBody compares the request bodies of the operations. A new
+// required body, a body that becomes required and a removed content type
+// are breaking.
+func (c *comparer) compareRequestBody(base, cur any) {
+	baseBody, _ := resolve(c.baseline, base)
+	curBody, _ := resolve(c.current, cur)
+	switch {
+	case baseBody == nil && curBody == nil:
+		return
+	case baseBody == nil && isTrue(curBody["required"]):
+		c.add(true, "required request body added")
+		return
+	case baseBody == nil:
+		c.add(false, "optional request body added")
+		return
+	case curBody == nil:
+		c.add(false, "request body removed")
+		return
+	}
+	if !isTrue(baseBody["required"]) && isTrue(curBody["required"]) {
+		c.add(true, "request body became required")
+	}
+
+	baseContent, _ := baseBody["content"].(map[string]any)
+	curContent, _ := curBody["content"].(map[string]any)
+	for _, mediaType := range sortedKeys(baseContent) {
+		curMedia, ok := curContent[mediaType].(map[string]any)
+		if !ok {
+			c.add(true, "request content type %s removed", mediaType)
+			continue
+		}
+		baseMedia, _ := baseContent[mediaType].(map[string]any)
+		c.compareSchema(baseMedia["schema"], curMedia["schema"], "request body "+mediaType, true)
+	}
+	for _, mediaType := range sortedKeys(curContent) {
+		if _, ok := baseContent[mediaType]; !ok {
+			c.add(false, "request content type %s added", mediaType)
+		}
+	}
+}
+
+// compareResponses compares the responses of the operations. A removed
+// success response, a removed content type and a changed schema are breaking.
+func (c *comparer) compareResponses(base, cur any) {
+	baseResponses, _ := base.(map[string]any)
+	curResponses, _ := cur.(map[string]any)
+	for _, status := range sortedKeys(baseResponses) {
+		baseResp, _ := resolve(c.baseline, baseResponses[status])
+		curResp, ok := resolve(c.current, curResponses[status])
+		if !ok {
+			c.add(strings.HasPrefix(status, "2"), "response %s removed", status)
+			continue
+		}
+		baseContent, _ := baseResp["content"].(map[string]any)
+		curContent, _ := curResp["content"].(map[string]any)
+		for _, mediaType := range sortedKeys(baseContent) {
+			curMedia, ok := curContent[mediaType].(map[string]any)
+			if !ok {
+				c.add(true, "response %s content type %s removed", status, mediaType)
+				continue
+			}
+			baseMedia, _ := baseContent[mediaType].(map[string]any)
+			c.compareSchema(baseMedia["schema"], curMedia["schema"], "response "+status+" "+mediaType, false)
+		}
+	}
+	for _, status := range sortedKeys(curResponses) {
+		if _, ok := baseResponses[status]; !ok {
+			c.add(false, "response %s added", status)
+		}
+	}
+}
+
+// compareSchema compares the schemas of a request, if input is set, or of a
+// response.
+//
+// A changed type is breaking. For requests, a narrowed enum and a new
+// required property are breaking, for responses a removed property is.
+func (c *comparer) compareSchema(base, cur any, where string, input bool) {
+	baseSchema, baseRef := resolveRef(c.baseline, base)
+	curSchema, curRef := resolveRef(c.current, cur)
+	if baseSchema == nil || curSchema == nil {
+		return
+	}
+	if baseRef != "" && curRef != "" {
+		pair := [2]string{baseRef, curRef}
+		if c.seen[pair] {
+			return
+		}
+		if c.seen == nil {
+			c.seen = make(map[[2]string]bool)
+		}
+		c.seen[pair] = true
+	}
+
+	baseType, curType := schemaType(baseSchema), schemaType(curSchema)
+	if baseType != curType && baseType != "" && curType != "" {
+		c.add(true, "type of %s changed from %s to %s", where, baseType, curType)
+		return
+	}
+
+	c.compareEnum(baseSchema["enum"], curSchema["enum"], where, input)
+
+	baseProps, _ := baseSchema["properties"].(map[string]any)
+	curProps, _ := curSchema["properties"].(map[string]any)
+	baseRequired, curRequired := stringSet(baseSchema["required"]), stringSet(curSchema["required"])
+	for _, name := range sortedKeys(baseProps) {
+		if _, ok := curProps[name]; !ok {
+			c.add(!input, "property %s removed from %s", name, where)
+		}
+	}
+	for _, name := range sortedKeys(curProps) {
+		if _, ok := baseProps[name]; !ok {
+			c.add(input && curRequired[name], "property %s added to %s", name, where)
+			continue
+		}
+		if input && curRequired[name] && !baseRequired[name] {
+			c.add(true, "property %s of %s became required", name, where)
+		}
+		c.compareSchema(baseProps[name], curProps[name], where+"."+name, input)
+	}
+
+	if baseSchema["items"] != nil && curSchema["items"] != nil {
+		c.compareSchema(baseSchema["items"], curSchema["items"], where+"[]", input)
+	}
+}
+
+// compareEnum compares the enums of a schema. Requests break when values are
+// removed, responses when values are added.
+func (c *comparer) compareEnum(base, cur any, where string, input bool) {
+	baseEnum, _ := base.([]any)
+	curEnum, _ := cur.([]any)
+	if base == nil && cur == nil {
+		return
+	}
+	if base == nil {
+		c.add(input, "enum of %s narrowed to %v", where, curEnum)
+		return
+	}
+	if cur == nil {
+		c.add(!input, "enum of %s removed", where)
+		return
+	}
+	if removed := missing(baseEnum, curEnum); len(removed) > 0 {
+		c.add(input, "enum of %s narrowed, %v removed", where, removed)
+	}
+	if added := missing(curEnum, baseEnum); len(added) > 0 {
+		c.add(!input, "enum of %s widened, %v added", where, added)
+	}
+}
+
+// missing returns the values of a that are not in b.
+func missing(a, b []any) []any {
+	var values []any
+	for _, v := range a {
+		found := false
+		for _, w := range b {
+			if reflect.DeepEqual(v, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// resolve returns the object v, following its reference to the components of doc.
+func resolve(doc map[string]any, v any) (map[string]any, bool) {
+	obj, _ := resolveRef(doc, v)
+	return obj, obj != nil
+}
+
+// resolveRef returns the object v, following its reference, and the reference.
+func resolveRef(doc map[string]any, v any) (map[string]any, string) {
+	obj, _ := v.(map[string]any)
+	ref, _ := obj["$ref"].(string)
+	if ref == "" {
+		return obj, ""
+	}
+	return jsonref.Resolve(doc, ref), ref
+}
+
+// schemaType returns the type of a schema, the types of OpenAPI 3.1 being joined.
+func schemaType(schema map[string]any) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, e := range t {
+			types = append(types, fmt.Sprint(e))
+		}
+		return strings.Join(types, "|")
+	}
+	return ""
+}
+
+func stringSet(v any) map[string]bool {
+	list, _ := v.([]any)
+	set := make(map[string]bool, len(list))
+	for _, e := range list {
+		if s, ok := e.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+func isTrue(v any) bool {
+	b, _ := v.(bool)
+	return b
+}
@@ -0,0 +1,232 @@
+// Package specdiff compares two OpenAPI documents and classifies the
+// changes that break the clients of the baseline document.
+package specdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/oaswrap/fiberopenapi/internal/util"
+	"gopkg.in/yaml.v3"
+)
+
+// Change is a change of an operation.
+type Change struct {
+	// Operation is the method and path of the operation, e.g. "GET /pets/{id}".
+	Operation string
+	// Message describes the change.
+	Message string
+	// Breaking reports whether the change breaks the clients of the baseline.
+	Breaking bool
+}
+
+func (c Change) String() string {
+	if c.Breaking {
+		return c.Operation + ": " + c.Message + " (breaking)"
+	}
+	return c.Operation + ": " + c.Message
+}
+
+// Diff is the difference between a baseline document and the current one.
+type Diff struct {
+	// BaselineVersion and Version are the info.version of the documents.
+	BaselineVersion string
+	Version         string
+
+	// Added and Removed are the operations added and removed, as "METHOD path".
+	Added   []string
+	Removed []string
+	// Changed are the operations found in both documents that differ.
+	Changed []string
+	// Changes are the changes found in the changed operations.
+	Changes []Change
+}
+
+// HasChanges reports whether the documents have different operations.
+func (d *Diff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0
+}
+
+// Breaking returns the changes breaking the clients of the baseline,
+// removed operations included.
+func (d *Diff) Breaking() []Change {
+	var breaking []Change
+	for _, op := range d.Removed {
+		breaking = append(breaking, Change{Operation: op, Message: "operation removed", Breaking: true})
+	}
+	for _, c := range d.Changes {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
+// VersionBumped reports whether the version is a new major version of the
+// baseline version, or a new minor version for versions 0.x.
+// Versions that are not semantic versions are bumped when they differ.
+func (d *Diff) VersionBumped() bool {
+	base, ok1 := parseVersion(d.BaselineVersion)
+	cur, ok2 := parseVersion(d.Version)
+	if !ok1 || !ok2 {
+		return d.BaselineVersion != d.Version
+	}
+	if base[0] == 0 && cur[0] == 0 {
+		return cur[1] > base[1]
+	}
+	return cur[0] > base[0]
+}
+
+func (d *Diff) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "version %s -> %s\n", d.BaselineVersion, d.Version)
+	for _, op := range d.Added {
+		fmt.Fprintf(&sb, "added %s\n", op)
+	}
+	for _, op := range d.Removed {
+		fmt.Fprintf(&sb, "removed %s (breaking)\n", op)
+	}
+	for _, c := range d.Changes {
+		fmt.Fprintf(&sb, "changed %s\n", c)
+	}
+	return sb.String()
+}
+
+// Parse parses an OpenAPI document in JSON or YAML.
+func Parse(data []byte) (map[string]any, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	// The JSON round trip gives the same types for JSON and YAML documents.
+	data, err := json.Marshal(stringKeys(doc))
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, fmt.Errorf("not an OpenAPI document")
+	}
+	return m, nil
+}
+
+// Compare compares the current document with the baseline document.
+func Compare(baseline, current map[string]any) *Diff {
+	d := &Diff{
+		BaselineVersion: infoVersion(baseline),
+		Version:         infoVersion(current),
+	}
+	base, cur := operations(baseline), operations(current)
+	for _, key := range sortedKeys(cur) {
+		if _, ok := base[key]; !ok {
+			d.Added = append(d.Added, key)
+		}
+	}
+	for _, key := range sortedKeys(base) {
+		curOp, ok := cur[key]
+		if !ok {
+			d.Removed = append(d.Removed, key)
+			continue
+		}
+		baseOp := base[key]
+		// The operations are compared even when they are equal, since the
+		// components they refer to may differ.
+		c := &comparer{operation: key, baseline: baseline, current: current}
+		c.compareOperation(baseOp, curOp)
+		if len(c.changes) == 0 && !reflect.DeepEqual(baseOp, curOp) {
+			c.add(false, "operation changed")
+		}
+		if len(c.changes) > 0 {
+			d.Changed = append(d.Changed, key)
+			d.Changes = append(d.Changes, c.changes...)
+		}
+	}
+	return d
+}
+
+// operation is an operation of a document with the parameters of its path item.
+type operation struct {
+	op     map[string]any
+	params []any
+}
+
+func operations(doc map[string]any) map[string]operation {
+	ops := make(map[string]operation)
+	paths, _ := doc["paths"].(map[string]any)
+	for path, item := range paths {
+		item, _ := item.(map[string]any)
+		pathParams, _ := item["parameters"].([]any)
+		for _, method := range util.OperationMethods {
+			if op, ok := item[method].(map[string]any); ok {
+				ops[strings.ToUpper(method)+" "+path] = operation{op: op, params: pathParams}
+			}
+		}
+	}
+	return ops
+}
+
+func infoVersion(doc map[string]any) string {
+	info, _ := doc["info"].(map[string]any)
+	version, _ := info["version"].(string)
+	return version
+}
+
+// parseVersion parses a semantic version, with an optional "v" prefix.
+func parseVersion(v string) ([3]int, bool) {
+	var version [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return version, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return version, false
+		}
+		version[i] = n
+	}
+	return version, true
+}
+
+// stringKeys converts the maps with non-string keys decoded from YAML.
+func stringKeys(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = stringKeys(e)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = stringKeys(e)
+		}
+		return m
+	case []any:
+		for i, e := range v {
+			v[i] = stringKeys(e)
+		}
+		return v
+	}
+	return v
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
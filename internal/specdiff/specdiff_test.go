@@ -0,0 +1,181 @@
+package specdiff_test
+
+import (
+	"testing"
+
+	"github.com/oaswrap/fiberopenapi/internal/specdiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baseline = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.2.0
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: status
+          in: query
+          schema:
+            type: string
+            enum: [available, pending, sold]
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        "201":
+          description: Created
+  /pets/{id}:
+    delete:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "204":
+          description: Deleted
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+        tag:
+          type: string
+`
+
+const current = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Pets", "version": "1.3.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "parameters": [
+          {"name": "limit", "in": "query", "schema": {"type": "string"}},
+          {"name": "status", "in": "query", "schema": {"type": "string", "enum": ["available", "sold"]}},
+          {"name": "X-Tenant", "in": "header", "required": true, "schema": {"type": "string"}},
+          {"name": "page", "in": "query", "schema": {"type": "integer"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}}}
+          }
+        }
+      },
+      "post": {
+        "summary": "Create a pet",
+        "requestBody": {
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}
+        },
+        "responses": {"201": {"description": "Created"}}
+      }
+    },
+    "/stores": {
+      "get": {"responses": {"200": {"description": "OK"}}}
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "required": ["name", "kind"],
+        "properties": {
+          "id": {"type": "integer"},
+          "name": {"type": "string"},
+          "kind": {"type": "string"}
+        }
+      }
+    }
+  }
+}`
+
+func TestCompare(t *testing.T) {
+	base, err := specdiff.Parse([]byte(baseline))
+	require.NoError(t, err)
+	cur, err := specdiff.Parse([]byte(current))
+	require.NoError(t, err)
+
+	diff := specdiff.Compare(base, cur)
+	assert.True(t, diff.HasChanges())
+	assert.Equal(t, []string{"GET /stores"}, diff.Added)
+	assert.Equal(t, []string{"DELETE /pets/{id}"}, diff.Removed)
+	assert.Equal(t, []string{"GET /pets", "POST /pets"}, diff.Changed)
+
+	var messages []string
+	for _, c := range diff.Changes {
+		messages = append(messages, c.String())
+	}
+	assert.Equal(t, []string{
+		"GET /pets: required parameter header X-Tenant added (breaking)",
+		"GET /pets: type of parameter query limit changed from integer to string (breaking)",
+		"GET /pets: optional parameter query page added",
+		"GET /pets: enum of parameter query status narrowed, [pending] removed (breaking)",
+		"GET /pets: property tag removed from response 200 application/json[] (breaking)",
+		"GET /pets: property kind added to response 200 application/json[]",
+		"POST /pets: property tag removed from request body application/json",
+		"POST /pets: property kind added to request body application/json (breaking)",
+	}, messages)
+
+	breaking := diff.Breaking()
+	require.Len(t, breaking, 6)
+	assert.Equal(t, specdiff.Change{Operation: "DELETE /pets/{id}", Message: "operation removed", Breaking: true}, breaking[0])
+	assert.False(t, diff.VersionBumped())
+}
+
+func TestCompare_Unchanged(t *testing.T) {
+	base, err := specdiff.Parse([]byte(baseline))
+	require.NoError(t, err)
+	cur, err := specdiff.Parse([]byte(baseline))
+	require.NoError(t, err)
+
+	diff := specdiff.Compare(base, cur)
+	assert.False(t, diff.HasChanges())
+	assert.Empty(t, diff.Breaking())
+}
+
+func TestDiff_VersionBumped(t *testing.T) {
+	tests := []struct {
+		baseline, version string
+		want              bool
+	}{
+		{"1.2.0", "2.0.0", true},
+		{"1.2.0", "1.3.0", false},
+		{"v1.2.0", "v2.0.0-rc.1", true},
+		{"0.2.0", "0.3.0", true},
+		{"0.2.0", "0.2.1", false},
+		{"2024-01", "2024-02", true},
+		{"2024-01", "2024-01", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.baseline+" to "+tt.version, func(t *testing.T) {
+			diff := &specdiff.Diff{BaselineVersion: tt.baseline, Version: tt.version}
+			assert.Equal(t, tt.want, diff.VersionBumped())
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package util
+
+// OperationMethods are the keys of the operations of an OpenAPI path item, in
+// the order of the specification.
+var OperationMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// IsOperationMethod reports whether key is the key of an operation of an
+// OpenAPI path item.
+func IsOperationMethod(key string) bool {
+	for _, method := range OperationMethods {
+		if key == method {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// PathParamNames returns the names of the path parameters declared with the
+// "path" tag by the given request structures, including embedded structs.
+func PathParamNames(structures ...any) map[string]bool {
+	names := make(map[string]bool)
+	for _, s := range structures {
+		collectPathParamNames(reflect.TypeOf(s), names)
+	}
+	return names
+}
+
+func collectPathParamNames(t reflect.Type, names map[string]bool) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name, ok := field.Tag.Lookup("path"); ok && name != "" && name != "-" {
+			names[name] = true
+			continue
+		}
+		if field.Anonymous {
+			collectPathParamNames(field.Type, names)
+		}
+	}
+}
+
+// PathParamsStruct builds a request structure declaring the given parameters
+// as path parameters, using the schema hints of each parameter.
+//
+// It returns nil if params is empty.
+func PathParamsStruct(params []Param) any {
+	if len(params) == 0 {
+		return nil
+	}
+	fields := make([]reflect.StructField, 0, len(params))
+	for i, p := range params {
+		fields = append(fields, reflect.StructField{
+			Name: "P" + strconv.Itoa(i),
+			Type: paramType(p),
+			Tag:  paramTag(p),
+		})
+	}
+	return reflect.New(reflect.StructOf(fields)).Interface()
+}
+
+func paramType(p Param) reflect.Type {
+	switch p.Type {
+	case "integer":
+		return reflect.TypeOf(int64(0))
+	case "number":
+		return reflect.TypeOf(float64(0))
+	case "boolean":
+		return reflect.TypeOf(false)
+	default:
+		return reflect.TypeOf("")
+	}
+}
+
+func paramTag(p Param) reflect.StructTag {
+	tag := `path:` + strconv.Quote(p.Name)
+	if p.Format != "" {
+		tag += ` format:` + strconv.Quote(p.Format)
+	}
+	if p.Pattern != "" {
+		tag += ` pattern:` + strconv.Quote(p.Pattern)
+	}
+	if p.Minimum != nil {
+		tag += ` minimum:` + strconv.Quote(strconv.FormatFloat(*p.Minimum, 'f', -1, 64))
+	}
+	if p.Maximum != nil {
+		tag += ` maximum:` + strconv.Quote(strconv.FormatFloat(*p.Maximum, 'f', -1, 64))
+	}
+	if p.MinLength != nil {
+		tag += ` minLength:` + strconv.Quote(strconv.FormatInt(*p.MinLength, 10))
+	}
+	if p.MaxLength != nil {
+		tag += ` maxLength:` + strconv.Quote(strconv.FormatInt(*p.MaxLength, 10))
+	}
+	return reflect.StructTag(tag)
+}
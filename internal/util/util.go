@@ -1,9 +1,337 @@
 package util
 
-import "regexp"
+import (
+	"strconv"
+	"strings"
+	"time"
+)
 
-// ConvertPath converts ":param" to "{param}" anywhere in the path.
+// Param describes a route parameter parsed from a Fiber path.
+type Param struct {
+	// Name is the parameter name used in the OpenAPI path template.
+	Name string
+	// FiberName is the name Fiber uses for the parameter, as accepted by c.Params.
+	FiberName string
+	// Optional reports whether the parameter was declared optional (":name?").
+	Optional bool
+	// Greedy reports whether the parameter is a wildcard ("*") or plus ("+") parameter.
+	Greedy bool
+
+	// Type, Format and Pattern are the JSON schema hints derived from the constraints.
+	Type    string
+	Format  string
+	Pattern string
+	// Minimum and Maximum are derived from the min, max and range constraints.
+	Minimum *float64
+	Maximum *float64
+	// MinLength and MaxLength are derived from the minLen, maxLen, len and betweenLen constraints.
+	MinLength *int64
+	MaxLength *int64
+}
+
+// Path is an OpenAPI path template together with its parameters.
+type Path struct {
+	Template string
+	Params   []Param
+}
+
+// ConvertPath converts a Fiber route path to an OpenAPI path template.
+//
+// Optional parameters are kept in the returned template; use ParsePath to get
+// every variant of a route with optional parameters.
 func ConvertPath(path string) string {
-	re := regexp.MustCompile(`:([a-zA-Z0-9_]+)`)
-	return re.ReplaceAllString(path, `{$1}`)
+	return ParsePath(path)[0].Template
+}
+
+// ParsePath parses a Fiber route path into OpenAPI path templates.
+//
+// It supports named parameters (":id"), optional parameters (":id?"),
+// wildcards ("*"), plus parameters ("+"), constraints (":id<int;min(1)>"),
+// parameters separated by "-" or "." (":from-:to") and escaped characters.
+//
+// Since OpenAPI has no notion of optional path parameters, a route with
+// optional parameters produces one template for every combination of them.
+// The first template always contains all parameters.
+func ParsePath(path string) []Path {
+	segs := parseSegments(path)
+
+	var optionals []int
+	for i, seg := range segs {
+		if seg.param != nil && seg.param.Optional {
+			optionals = append(optionals, i)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var paths []Path
+	for mask := 0; mask < 1<<len(optionals); mask++ {
+		omit := make(map[int]bool)
+		for bit, idx := range optionals {
+			if mask&(1<<bit) != 0 {
+				omit[idx] = true
+			}
+		}
+		p := buildPath(segs, omit, strings.HasPrefix(path, "/"))
+		if seen[p.Template] {
+			continue
+		}
+		seen[p.Template] = true
+		paths = append(paths, p)
+	}
+
+	return paths
+}
+
+type segment struct {
+	text  string
+	param *Param
+}
+
+func buildPath(segs []segment, omit map[int]bool, rooted bool) Path {
+	var sb strings.Builder
+	var params []Param
+	for i, seg := range segs {
+		if seg.param == nil {
+			sb.WriteString(seg.text)
+			continue
+		}
+		if omit[i] {
+			// The slash in front of an omitted optional parameter is optional too.
+			s := sb.String()
+			if strings.HasSuffix(s, "/") {
+				sb.Reset()
+				sb.WriteString(s[:len(s)-1])
+			}
+			continue
+		}
+		sb.WriteString("{" + seg.param.Name + "}")
+		params = append(params, *seg.param)
+	}
+
+	template := sb.String()
+	if template == "" && rooted {
+		template = "/"
+	}
+
+	return Path{Template: template, Params: params}
+}
+
+func parseSegments(path string) []segment {
+	var (
+		segs          []segment
+		text          strings.Builder
+		wildcardCount int
+		plusCount     int
+	)
+	flush := func() {
+		if text.Len() > 0 {
+			segs = append(segs, segment{text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c == '\\' && i+1 < len(path):
+			i++
+			text.WriteByte(path[i])
+		case c == '*' || c == '+':
+			flush()
+			param := &Param{Greedy: true}
+			if c == '*' {
+				wildcardCount++
+				param.Name = greedyName("wildcard", wildcardCount)
+				param.FiberName = "*" + strconv.Itoa(wildcardCount)
+			} else {
+				plusCount++
+				param.Name = greedyName("plus", plusCount)
+				param.FiberName = "+" + strconv.Itoa(plusCount)
+			}
+			segs = append(segs, segment{param: param})
+		case c == ':' && i+1 < len(path) && isNameChar(path[i+1]):
+			flush()
+			var param *Param
+			param, i = parseParam(path, i+1)
+			segs = append(segs, segment{param: param})
+		default:
+			text.WriteByte(c)
+		}
+	}
+	flush()
+
+	return segs
+}
+
+// parseParam parses a named parameter starting at start, which is the first
+// character after ":". It returns the parameter and the index of its last character.
+func parseParam(path string, start int) (*Param, int) {
+	end := start
+	for end < len(path) && isNameChar(path[end]) {
+		end++
+	}
+	param := &Param{Name: path[start:end], FiberName: path[start:end]}
+
+	if end < len(path) && path[end] == '<' {
+		depth := 0
+		closing := -1
+		for j := end + 1; j < len(path) && closing == -1; j++ {
+			switch path[j] {
+			case '\\':
+				j++
+			case '(':
+				depth++
+			case ')':
+				depth--
+			case '>':
+				if depth == 0 {
+					closing = j
+				}
+			}
+		}
+		if closing != -1 {
+			applyConstraints(param, path[end+1:closing])
+			end = closing + 1
+		}
+	}
+
+	if end < len(path) && path[end] == '?' {
+		param.Optional = true
+		end++
+	}
+
+	return param, end - 1
+}
+
+func isNameChar(c byte) bool {
+	switch c {
+	case '/', '-', '.', ':', '\\', '?', '<', '*', '+':
+		return false
+	}
+	return true
+}
+
+func greedyName(base string, n int) string {
+	if n == 1 {
+		return base
+	}
+	return base + strconv.Itoa(n)
+}
+
+// applyConstraints maps Fiber route constraints to JSON schema hints.
+func applyConstraints(param *Param, constraints string) {
+	for _, c := range splitTopLevel(constraints, ';') {
+		name, data := c, ""
+		if open := strings.IndexByte(c, '('); open != -1 && strings.HasSuffix(c, ")") {
+			name, data = c[:open], c[open+1:len(c)-1]
+		}
+		args := splitTopLevel(data, ',')
+
+		switch name {
+		case "int":
+			param.Type = "integer"
+		case "bool":
+			param.Type = "boolean"
+		case "float":
+			param.Type = "number"
+		case "alpha":
+			// Fiber accepts any Unicode letter, which JSON schema patterns
+			// can not portably express.
+			param.Type = "string"
+		case "guid":
+			param.Type = "string"
+			param.Format = "uuid"
+		case "datetime":
+			param.Type = "string"
+			param.Format = datetimeFormat(data)
+		case "regex":
+			param.Type = "string"
+			param.Pattern = data
+		case "minLen":
+			param.MinLength = parseInt(args, 0)
+		case "maxLen":
+			param.MaxLength = parseInt(args, 0)
+		case "len":
+			param.MinLength = parseInt(args, 0)
+			param.MaxLength = parseInt(args, 0)
+		case "betweenLen":
+			param.MinLength = parseInt(args, 0)
+			param.MaxLength = parseInt(args, 1)
+		case "min":
+			param.Minimum = parseFloat(args, 0)
+		case "max":
+			param.Maximum = parseFloat(args, 0)
+		case "range":
+			param.Minimum = parseFloat(args, 0)
+			param.Maximum = parseFloat(args, 1)
+		}
+	}
+
+	// Numeric bounds without an explicit type only make sense for numbers,
+	// Fiber parses them as integers.
+	if param.Type == "" && (param.Minimum != nil || param.Maximum != nil) {
+		param.Type = "integer"
+	}
+	if param.Type == "" && (param.MinLength != nil || param.MaxLength != nil) {
+		param.Type = "string"
+	}
+}
+
+// datetimeFormat returns the schema format of the values of a datetime
+// constraint with the given layout, empty when no format matches the layout.
+func datetimeFormat(layout string) string {
+	switch strings.ReplaceAll(layout, `\`, "") {
+	case time.RFC3339, time.RFC3339Nano:
+		return "date-time"
+	case time.DateOnly:
+		return "date"
+	}
+	return ""
+}
+
+// splitTopLevel splits s by sep, ignoring separators inside parentheses or escaped.
+func splitTopLevel(s string, sep byte) []string {
+	if s == "" {
+		return nil
+	}
+	var parts []string
+	depth, last := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[last:i]))
+				last = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(s[last:]))
+}
+
+func parseInt(args []string, i int) *int64 {
+	if i >= len(args) {
+		return nil
+	}
+	v, err := strconv.ParseInt(args[i], 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
+
+func parseFloat(args []string, i int) *float64 {
+	if i >= len(args) {
+		return nil
+	}
+	v, err := strconv.ParseFloat(args[i], 64)
+	if err != nil {
+		return nil
+	}
+	return &v
 }
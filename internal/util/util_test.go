@@ -1,10 +1,12 @@
 package util_test
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/oaswrap/fiberopenapi/internal/util"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConvertPath(t *testing.T) {
@@ -62,3 +64,210 @@ func TestConvertPath(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertPath_FiberSyntax(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "optional parameter",
+			input:    "/users/:id?",
+			expected: "/users/{id}",
+		},
+		{
+			name:     "wildcard",
+			input:    "/files/*",
+			expected: "/files/{wildcard}",
+		},
+		{
+			name:     "plus",
+			input:    "/files/+",
+			expected: "/files/{plus}",
+		},
+		{
+			name:     "multiple wildcards",
+			input:    "/files/*/to/*",
+			expected: "/files/{wildcard}/to/{wildcard2}",
+		},
+		{
+			name:     "constraints",
+			input:    "/users/:id<int;min(1)>",
+			expected: "/users/{id}",
+		},
+		{
+			name:     "regex constraint with delimiters",
+			input:    `/dates/:date<regex(\d{4}-\d{2}-\d{2})>/events`,
+			expected: "/dates/{date}/events",
+		},
+		{
+			name:     "hyphen separated parameters",
+			input:    "/flights/:from-:to",
+			expected: "/flights/{from}-{to}",
+		},
+		{
+			name:     "dot separated parameters",
+			input:    "/plants/:genus.:species",
+			expected: "/plants/{genus}.{species}",
+		},
+		{
+			name:     "escaped colon",
+			input:    `/api/v1\:batch`,
+			expected: "/api/v1:batch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := util.ConvertPath(tt.input)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	t.Run("optional parameters", func(t *testing.T) {
+		paths := util.ParsePath("/users/:id?")
+		require.Len(t, paths, 2)
+		assert.Equal(t, "/users/{id}", paths[0].Template)
+		assert.Equal(t, "/users", paths[1].Template)
+		require.Len(t, paths[0].Params, 1)
+		assert.True(t, paths[0].Params[0].Optional)
+		assert.Empty(t, paths[1].Params)
+	})
+	t.Run("optional parameter at root", func(t *testing.T) {
+		paths := util.ParsePath("/:lang?")
+		require.Len(t, paths, 2)
+		assert.Equal(t, "/{lang}", paths[0].Template)
+		assert.Equal(t, "/", paths[1].Template)
+	})
+	t.Run("multiple optional parameters", func(t *testing.T) {
+		paths := util.ParsePath("/:a?/:b?")
+		var templates []string
+		for _, p := range paths {
+			templates = append(templates, p.Template)
+		}
+		assert.Equal(t, []string{"/{a}/{b}", "/{b}", "/{a}", "/"}, templates)
+	})
+	t.Run("greedy parameters", func(t *testing.T) {
+		paths := util.ParsePath("/files/*/+")
+		require.Len(t, paths, 1)
+		require.Len(t, paths[0].Params, 2)
+		assert.Equal(t, util.Param{Name: "wildcard", FiberName: "*1", Greedy: true}, paths[0].Params[0])
+		assert.Equal(t, util.Param{Name: "plus", FiberName: "+1", Greedy: true}, paths[0].Params[1])
+	})
+	t.Run("constraints", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected util.Param
+		}{
+			{
+				input:    "/:id<int>",
+				expected: util.Param{Name: "id", FiberName: "id", Type: "integer"},
+			},
+			{
+				input:    "/:id<int;min(1);max(10)>",
+				expected: util.Param{Name: "id", FiberName: "id", Type: "integer", Minimum: ptr(1.0), Maximum: ptr(10.0)},
+			},
+			{
+				input:    "/:age<range(18,99)>",
+				expected: util.Param{Name: "age", FiberName: "age", Type: "integer", Minimum: ptr(18.0), Maximum: ptr(99.0)},
+			},
+			{
+				input:    "/:active<bool>",
+				expected: util.Param{Name: "active", FiberName: "active", Type: "boolean"},
+			},
+			{
+				input:    "/:price<float>",
+				expected: util.Param{Name: "price", FiberName: "price", Type: "number"},
+			},
+			{
+				input:    "/:name<alpha>",
+				expected: util.Param{Name: "name", FiberName: "name", Type: "string"},
+			},
+			{
+				input:    "/:id<guid>",
+				expected: util.Param{Name: "id", FiberName: "id", Type: "string", Format: "uuid"},
+			},
+			{
+				input:    "/:at<datetime(2006\\-01\\-02)>",
+				expected: util.Param{Name: "at", FiberName: "at", Type: "string", Format: "date"},
+			},
+			{
+				input:    "/:at<datetime(2006\\-01\\-02T15\\:04\\:05Z07\\:00)>",
+				expected: util.Param{Name: "at", FiberName: "at", Type: "string", Format: "date-time"},
+			},
+			{
+				input:    "/:at<datetime(02\\.01\\.2006)>",
+				expected: util.Param{Name: "at", FiberName: "at", Type: "string"},
+			},
+			{
+				input:    `/:code<regex(^[A-Z]{3}$)>`,
+				expected: util.Param{Name: "code", FiberName: "code", Type: "string", Pattern: "^[A-Z]{3}$"},
+			},
+			{
+				input:    "/:slug<minLen(2);maxLen(8)>",
+				expected: util.Param{Name: "slug", FiberName: "slug", Type: "string", MinLength: ptr(int64(2)), MaxLength: ptr(int64(8))},
+			},
+			{
+				input:    "/:slug<betweenLen(2,8)>",
+				expected: util.Param{Name: "slug", FiberName: "slug", Type: "string", MinLength: ptr(int64(2)), MaxLength: ptr(int64(8))},
+			},
+			{
+				input:    "/:pin<len(4)>?",
+				expected: util.Param{Name: "pin", FiberName: "pin", Type: "string", MinLength: ptr(int64(4)), MaxLength: ptr(int64(4)), Optional: true},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.input, func(t *testing.T) {
+				paths := util.ParsePath(tt.input)
+				require.NotEmpty(t, paths)
+				require.Len(t, paths[0].Params, 1)
+				assert.Equal(t, tt.expected, paths[0].Params[0])
+			})
+		}
+	})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestPathParamNames(t *testing.T) {
+	type Embedded struct {
+		OrgID string `path:"orgId"`
+	}
+	type Request struct {
+		Embedded
+		ID      int64  `path:"id"`
+		Ignored string `path:"-"`
+		Query   string `query:"q"`
+	}
+
+	names := util.PathParamNames(new(Request), nil, "not a struct")
+	assert.Equal(t, map[string]bool{"orgId": true, "id": true}, names)
+}
+
+func TestPathParamsStruct(t *testing.T) {
+	t.Run("no params", func(t *testing.T) {
+		assert.Nil(t, util.PathParamsStruct(nil))
+	})
+	t.Run("with schema hints", func(t *testing.T) {
+		params := util.ParsePath(`/:id<int;min(1)>/:code<regex(^\d+$)>/:slug<len(3)>/:flag<bool>/:price<float>`)[0].Params
+		s := util.PathParamsStruct(params)
+		require.NotNil(t, s)
+
+		typ := reflect.TypeOf(s).Elem()
+		require.Equal(t, 5, typ.NumField())
+
+		assert.Equal(t, reflect.Int64, typ.Field(0).Type.Kind())
+		assert.Equal(t, `path:"id" minimum:"1"`, string(typ.Field(0).Tag))
+		assert.Equal(t, reflect.String, typ.Field(1).Type.Kind())
+		assert.Equal(t, `^\d+$`, typ.Field(1).Tag.Get("pattern"))
+		assert.Equal(t, `path:"slug" minLength:"3" maxLength:"3"`, string(typ.Field(2).Tag))
+		assert.Equal(t, reflect.Bool, typ.Field(3).Type.Kind())
+		assert.Equal(t, reflect.Float64, typ.Field(4).Type.Kind())
+		assert.Equal(t, util.PathParamNames(s), map[string]bool{"id": true, "code": true, "slug": true, "flag": true, "price": true})
+	})
+}
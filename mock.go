@@ -0,0 +1,120 @@
+package fiberopenapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi/internal/util"
+)
+
+// MockHandler is a placeholder handler for the routes that are documented
+// but not implemented yet. Registered through a router, it is replaced with
+// a handler answering with an example of the documented responses, like the
+// routes of a router with Config.MockResponses:
+//
+//	r.Get("/pets/:id", fiberopenapi.MockHandler).With(
+//		option.Response(200, new(Pet)),
+//		option.Response(404, new(ErrorResponse)),
+//	)
+//
+// Registered directly on Fiber, it answers 501 Not Implemented.
+func MockHandler(c *fiber.Ctx) error {
+	return fiber.ErrNotImplemented
+}
+
+var mockHandlerID = handlerID(MockHandler)
+
+// isMockHandler reports whether h is MockHandler.
+func isMockHandler(h fiber.Handler) bool {
+	return handlerID(h) == mockHandlerID
+}
+
+// mock answers with an example of a documented response of the route.
+//
+// The response is the first documented success response, or the response
+// with the status requested with a "Prefer: code=404" header. The body is
+// the example of the response, see Examples, in JSON unless the response
+// only has other content types.
+func (r *route) mock(c *fiber.Ctx) error {
+	doc, err := r.reg.document().parsedSpec()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "mock: "+err.Error())
+	}
+	template := util.ParsePath(r.path)[0].Template
+	paths, _ := doc["paths"].(map[string]any)
+	item, _ := paths[template].(map[string]any)
+	op, _ := item[strings.ToLower(r.method)].(map[string]any)
+	if op == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, fmt.Sprintf("mock: %s %s is not documented", r.method, template))
+	}
+	responses, _ := op["responses"].(map[string]any)
+
+	status, preferred := preferredStatus(c.Get("Prefer"))
+	if !preferred {
+		status = defaultStatus(responses)
+	}
+	response, ok := responses[strconv.Itoa(status)]
+	if !ok {
+		return fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("mock: %s %s has no documented response with status %d", r.method, template, status))
+	}
+	if preferred {
+		c.Set("Preference-Applied", "code="+strconv.Itoa(status))
+	}
+	c.Status(status)
+
+	example, ok := bodyExample(doc, response)
+	if !ok || c.Method() == fiber.MethodHead {
+		return nil
+	}
+	c.Set(fiber.HeaderContentType, example.ContentType)
+	if s, ok := example.Value.(string); ok && !strings.HasSuffix(example.ContentType, "json") {
+		return c.SendString(s)
+	}
+	body, err := json.Marshal(example.Value)
+	if err != nil {
+		return err
+	}
+	return c.Send(body)
+}
+
+// preferredStatus returns the status requested with the code preference of
+// a Prefer header, such as "Prefer: code=404".
+func preferredStatus(prefer string) (int, bool) {
+	for _, pref := range strings.FieldsFunc(prefer, func(r rune) bool { return r == ',' || r == ';' }) {
+		name, value, ok := strings.Cut(strings.TrimSpace(pref), "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), "code") {
+			continue
+		}
+		status, err := strconv.Atoi(strings.Trim(strings.TrimSpace(value), `"`))
+		if err == nil {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+// defaultStatus returns the lowest documented 2xx status, or the lowest
+// documented status if there is no success response.
+func defaultStatus(responses map[string]any) int {
+	var statuses []int
+	for code := range responses {
+		if status, err := strconv.Atoi(code); err == nil {
+			statuses = append(statuses, status)
+		}
+	}
+	if len(statuses) == 0 {
+		return fiber.StatusNoContent
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		if status >= 200 && status < 300 {
+			return status
+		}
+	}
+	return statuses[0]
+}
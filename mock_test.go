@@ -0,0 +1,150 @@
+package fiberopenapi_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi"
+	"github.com/oaswrap/spec/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPet struct {
+	ID     int       `json:"id" example:"42"`
+	Name   string    `json:"name" example:"Rex"`
+	Status string    `json:"status" enum:"available,sold"`
+	Tags   []string  `json:"tags"`
+	Born   time.Time `json:"born"`
+}
+
+type MockError struct {
+	Message string `json:"message" example:"pet not found"`
+}
+
+func TestRouter_MockResponses(t *testing.T) {
+	app := fiber.New()
+	r := fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{MockResponses: true})
+	used := 0
+	r.Use(func(c *fiber.Ctx) error {
+		used++
+		return c.Next()
+	})
+	r.Get("/pets/:id").With(
+		option.Response(200, new(MockPet)),
+		option.Response(404, new(MockError)),
+	)
+	r.Post("/pets", func(c *fiber.Ctx) error {
+		t.Error("expected the handler not to run")
+		return nil
+	}).With(option.Response(201, new(MockPet)))
+	r.Delete("/pets/:id")
+	r.Get("/internal", PingHandler).With(option.Hide())
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		prefer      string
+		status      int
+		body        string
+		contentType string
+		applied     string
+	}{
+		{
+			name:        "success response",
+			method:      "GET",
+			path:        "/pets/1",
+			status:      200,
+			body:        `{"born":"2024-01-02T15:04:05Z","id":42,"name":"Rex","status":"available","tags":["string"]}`,
+			contentType: "application/json",
+		},
+		{
+			name:        "preferred response",
+			method:      "GET",
+			path:        "/pets/1",
+			prefer:      "code=404",
+			status:      404,
+			body:        `{"message":"pet not found"}`,
+			contentType: "application/json",
+			applied:     "code=404",
+		},
+		{
+			name:   "undocumented preferred response",
+			method: "GET",
+			path:   "/pets/1",
+			prefer: "respond-async, code=500",
+			status: 400,
+			body:   "mock: GET /pets/{id} has no documented response with status 500",
+		},
+		{
+			name:        "created response",
+			method:      "POST",
+			path:        "/pets",
+			status:      201,
+			body:        `{"born":"2024-01-02T15:04:05Z","id":42,"name":"Rex","status":"available","tags":["string"]}`,
+			contentType: "application/json",
+		},
+		{
+			name:   "default response",
+			method: "DELETE",
+			path:   "/pets/1",
+			status: 204,
+		},
+		{
+			name:   "hidden route",
+			method: "GET",
+			path:   "/internal",
+			status: 501,
+			body:   "mock: GET /internal is not documented",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.prefer != "" {
+				req.Header.Set("Prefer", tt.prefer)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.body, string(body))
+			if tt.contentType != "" {
+				assert.Equal(t, tt.contentType, resp.Header.Get("Content-Type"))
+			}
+			assert.Equal(t, tt.applied, resp.Header.Get("Preference-Applied"))
+		})
+	}
+	assert.Equal(t, len(tests), used, "expected the middleware to run")
+}
+
+func TestMockHandler(t *testing.T) {
+	app := fiber.New()
+	r := fiberopenapi.NewRouter(app)
+	r.Get("/pets/:id", fiberopenapi.MockHandler).With(option.Response(200, new(MockPet)))
+	r.Get("/ping", PingHandler)
+	app.Get("/direct", fiberopenapi.MockHandler)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/pets/1", nil))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.JSONEq(t, `{"born":"2024-01-02T15:04:05Z","id":42,"name":"Rex","status":"available","tags":["string"]}`, string(body))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/ping", nil))
+	require.NoError(t, err)
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "pong", string(body), "expected the other routes not to be mocked")
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/direct", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 501, resp.StatusCode)
+}
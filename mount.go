@@ -0,0 +1,285 @@
+package fiberopenapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi/internal/util"
+	"github.com/oaswrap/spec"
+	"github.com/oaswrap/spec/option"
+	"github.com/swaggest/openapi-go/openapi3"
+	"github.com/swaggest/openapi-go/openapi31"
+)
+
+// mount is a generator mounted on a group with Router.Mount.
+type mount struct {
+	prefix string // full Fiber path prefix of the mount
+	reg    *registry
+	source SourceLocation
+}
+
+func (r *router) Mount(prefix string, sub Generator) Router {
+	child, ok := sub.Generator().(*router)
+	if !ok {
+		panic(fmt.Sprintf("fiberopenapi: Mount requires a generator created by fiberopenapi, got %T", sub))
+	}
+	app, ok := child.fiberRouter.(*fiber.App)
+	if !ok {
+		panic(fmt.Sprintf("fiberopenapi: Mount requires a generator created on a *fiber.App, got %T", child.fiberRouter))
+	}
+	if child.reg.mounts(r.reg) {
+		panic("fiberopenapi: Mount can not mount a router on itself, directly or through the generators mounted on it")
+	}
+
+	r.fiberRouter.Mount(prefix, app)
+	m := &mount{prefix: r.group.prefix + prefix, reg: child.reg, source: callerLocation()}
+	r.reg.update(func() {
+		r.group.mounts = append(r.group.mounts, m)
+		r.reg.mounted = append(r.reg.mounted, child.reg)
+	})
+
+	return r
+}
+
+// mountedSpec is a mount with the options of the groups it is mounted on.
+type mountedSpec struct {
+	*mount
+	groupOpts []option.GroupOption
+}
+
+// mergedSpec is the specification of a router merged with its mounted generators.
+type mergedSpec interface {
+	MarshalJSON() ([]byte, error)
+	MarshalYAML() ([]byte, error)
+}
+
+// mergeMounts merges the specifications of the mounted generators into the
+// specification of gen, then rewrites the merged specification with rewrite.
+//
+// Paths are prefixed with the mount prefix. Components and tags are shared,
+// a component defined differently by two specifications is an error.
+func mergeMounts(gen spec.Generator, mounts []mountedSpec, rewrite func(doc map[string]any)) (mergedSpec, error) {
+	schema, err := gen.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, m := range mounts {
+		if err := m.mergeInto(doc); err != nil {
+			errs = append(errs, &SourceError{Source: m.source, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	rewrite(doc)
+	return typedSpec(doc)
+}
+
+// typedSpec converts doc to the typed specification of its OpenAPI version,
+// which keeps the field order of the spec package when marshalled.
+func typedSpec(doc map[string]any) (mergedSpec, error) {
+	schema, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var typed mergedSpec = &openapi3.Spec{}
+	if version, _ := doc["openapi"].(string); strings.HasPrefix(version, "3.1") {
+		typed = &openapi31.Spec{}
+	}
+	if err := json.Unmarshal(schema, typed); err != nil {
+		return nil, err
+	}
+	return typed, nil
+}
+
+// groupConfig returns the configuration of the groups of the mount.
+func (m mountedSpec) groupConfig() *option.GroupConfig {
+	groupCfg := &option.GroupConfig{}
+	for _, opt := range m.groupOpts {
+		opt(groupCfg)
+	}
+	return groupCfg
+}
+
+// mergeInto merges the specification of the mounted generator into doc.
+func (m mountedSpec) mergeInto(doc map[string]any) error {
+	groupCfg := m.groupConfig()
+	if groupCfg.Hide {
+		return nil
+	}
+	if len(util.ParsePath(m.prefix)[0].Params) > 0 {
+		return fmt.Errorf("mount %s: path parameters are not supported in the mount prefix", m.prefix)
+	}
+
+	schema, err := m.reg.document().MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("mount %s: %w", m.prefix, err)
+	}
+	var sub map[string]any
+	if err := json.Unmarshal(schema, &sub); err != nil {
+		return fmt.Errorf("mount %s: %w", m.prefix, err)
+	}
+	if v, subV := minorVersion(doc), minorVersion(sub); v != subV {
+		return fmt.Errorf("mount %s: the mounted specification uses OpenAPI %s, the router uses OpenAPI %s", m.prefix, subV, v)
+	}
+
+	var errs []error
+	prefix := strings.TrimSuffix(m.prefix, "/")
+	paths := object(doc, "paths")
+	ids := documentOperationIDs(paths)
+	subPaths, _ := sub["paths"].(map[string]any)
+	for _, p := range sortedKeys(subPaths) {
+		item, _ := subPaths[p].(map[string]any)
+		for _, method := range sortedKeys(item) {
+			op, ok := item[method].(map[string]any)
+			if !ok || !util.IsOperationMethod(method) {
+				continue
+			}
+			mountOperation(op, sub["security"], groupCfg)
+			if id, _ := op["operationId"].(string); id != "" {
+				if ids[id] {
+					errs = append(errs, fmt.Errorf("mount %s: duplicate operationId %q", m.prefix, id))
+				}
+				ids[id] = true
+			}
+		}
+
+		full := prefix + p
+		if p == "/" && prefix != "" {
+			full = prefix
+		}
+		existing, ok := paths[full].(map[string]any)
+		if !ok {
+			paths[full] = item
+			continue
+		}
+		for _, key := range sortedKeys(item) {
+			if _, dup := existing[key]; !dup {
+				existing[key] = item[key]
+			} else if util.IsOperationMethod(key) {
+				errs = append(errs, fmt.Errorf("mount %s: %s %s is already documented by the router", m.prefix, strings.ToUpper(key), full))
+			} else if !reflect.DeepEqual(existing[key], item[key]) {
+				errs = append(errs, fmt.Errorf("mount %s: path %s has a different %s in the router", m.prefix, full, key))
+			}
+		}
+	}
+
+	subComponents, _ := sub["components"].(map[string]any)
+	for _, section := range sortedKeys(subComponents) {
+		entries, _ := subComponents[section].(map[string]any)
+		if len(entries) == 0 {
+			continue
+		}
+		components := object(object(doc, "components"), section)
+		for _, name := range sortedKeys(entries) {
+			existing, dup := components[name]
+			if !dup {
+				components[name] = entries[name]
+			} else if !reflect.DeepEqual(existing, entries[name]) {
+				errs = append(errs, fmt.Errorf("mount %s: component %s/%s is defined differently by the router", m.prefix, section, name))
+			}
+		}
+	}
+
+	subTags, _ := sub["tags"].([]any)
+	tags, _ := doc["tags"].([]any)
+	names := make(map[any]bool, len(tags))
+	for _, tag := range tags {
+		if tag, ok := tag.(map[string]any); ok {
+			names[tag["name"]] = true
+		}
+	}
+	for _, tag := range subTags {
+		if tag, ok := tag.(map[string]any); ok && !names[tag["name"]] {
+			tags = append(tags, tag)
+			names[tag["name"]] = true
+		}
+	}
+	if len(tags) > 0 {
+		doc["tags"] = tags
+	}
+
+	return errors.Join(errs...)
+}
+
+// mountOperation applies to an operation of a mounted specification the
+// security of that specification and the tags and security of the groups
+// it is mounted on.
+func mountOperation(op map[string]any, security any, groupCfg *option.GroupConfig) {
+	if _, ok := op["security"]; !ok && security != nil {
+		op["security"] = security
+	}
+	if len(groupCfg.Tags) > 0 {
+		tags, _ := op["tags"].([]any)
+		for _, tag := range groupCfg.Tags {
+			tags = append(tags, tag)
+		}
+		op["tags"] = tags
+	}
+	if len(groupCfg.Security) > 0 {
+		requirements, _ := op["security"].([]any)
+		for _, sec := range groupCfg.Security {
+			scopes := make([]any, 0, len(sec.Scopes))
+			for _, scope := range sec.Scopes {
+				scopes = append(scopes, scope)
+			}
+			requirements = append(requirements, map[string]any{sec.Name: scopes})
+		}
+		op["security"] = requirements
+	}
+}
+
+// documentOperationIDs returns the operationIds of the operations of paths.
+func documentOperationIDs(paths map[string]any) map[string]bool {
+	ids := make(map[string]bool)
+	for _, item := range paths {
+		item, _ := item.(map[string]any)
+		for method, op := range item {
+			if op, ok := op.(map[string]any); ok && util.IsOperationMethod(method) {
+				if id, _ := op["operationId"].(string); id != "" {
+					ids[id] = true
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// object returns the object at key in m, adding it if it is missing.
+func object(m map[string]any, key string) map[string]any {
+	obj, ok := m[key].(map[string]any)
+	if !ok {
+		obj = make(map[string]any)
+		m[key] = obj
+	}
+	return obj
+}
+
+// minorVersion returns the major and minor version of the OpenAPI document.
+func minorVersion(doc map[string]any) string {
+	version, _ := doc["openapi"].(string)
+	if parts := strings.SplitN(version, ".", 3); len(parts) == 3 {
+		return parts[0] + "." + parts[1]
+	}
+	return version
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
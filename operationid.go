@@ -0,0 +1,80 @@
+package fiberopenapi
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/oaswrap/fiberopenapi/internal/util"
+)
+
+// OperationIDFunc returns the operationId of an operation from its method
+// and its OpenAPI path template, see Config.OperationID.
+type OperationIDFunc func(method, path string) string
+
+// DefaultOperationID builds an operationId from the method and the path
+// segments in camel case, path parameters being prefixed with "By".
+//
+// For example GET /users/{id}/posts gives "getUsersByIdPosts".
+func DefaultOperationID(method, path string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(method))
+	for _, seg := range strings.Split(path, "/") {
+		for _, part := range strings.FieldsFunc(seg, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '{' && r != '}'
+		}) {
+			if strings.HasPrefix(part, "{") {
+				sb.WriteString("By")
+			}
+			sb.WriteString(upperFirst(strings.Trim(part, "{}")))
+		}
+	}
+	return sb.String()
+}
+
+// operationIDs holds the settings of the operationIds of a router tree.
+type operationIDs struct {
+	fromName bool
+	fallback OperationIDFunc
+}
+
+// operationID returns the operationId of a path variant of a route.
+//
+// id is the operationId set by the route options. The variants of a route
+// with optional parameters other than the first one, which has all the
+// parameters, have the omitted parameters appended to the id of the route.
+func (o operationIDs) operationID(id, name, method string, variant util.Path, first util.Path) string {
+	if id == "" && o.fromName {
+		id = name
+	}
+	if id == "" {
+		if o.fallback != nil {
+			return o.fallback(method, variant.Template)
+		}
+		return ""
+	}
+	if len(variant.Params) == len(first.Params) {
+		return id
+	}
+
+	present := make(map[string]bool, len(variant.Params))
+	for _, p := range variant.Params {
+		present[p.Name] = true
+	}
+	var sb strings.Builder
+	sb.WriteString(id + "Without")
+	for _, p := range first.Params {
+		if !present[p.Name] {
+			sb.WriteString(upperFirst(p.Name))
+		}
+	}
+	return sb.String()
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
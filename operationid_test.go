@@ -0,0 +1,106 @@
+package fiberopenapi_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi"
+	"github.com/oaswrap/spec/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultOperationID(t *testing.T) {
+	tests := []struct {
+		method, path string
+		want         string
+	}{
+		{"GET", "/", "get"},
+		{"GET", "/users", "getUsers"},
+		{"GET", "/users/{id}/posts", "getUsersByIdPosts"},
+		{"POST", "/api/v1/user-groups", "postApiV1UserGroups"},
+		{"DELETE", "/files/{from}-{to}", "deleteFilesByFromByTo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, fiberopenapi.DefaultOperationID(tt.method, tt.path))
+		})
+	}
+}
+
+func TestRouter_OperationID(t *testing.T) {
+	operationIDs := func(t *testing.T, r fiberopenapi.Generator) map[string]string {
+		t.Helper()
+		schema, err := r.MarshalJSON()
+		require.NoError(t, err)
+		var doc struct {
+			Paths map[string]map[string]struct {
+				OperationID string `json:"operationId"`
+			} `json:"paths"`
+		}
+		require.NoError(t, json.Unmarshal(schema, &doc))
+		ids := make(map[string]string)
+		for path, item := range doc.Paths {
+			for method, op := range item {
+				ids[method+" "+path] = op.OperationID
+			}
+		}
+		return ids
+	}
+
+	t.Run("from the route name", func(t *testing.T) {
+		r := fiberopenapi.NewRouterWithConfig(fiber.New(), fiberopenapi.Config{NameOperationIDs: true})
+		r.Get("/users", PingHandler).Name("listUsers")
+		r.Get("/users/:id/:tab?", PingHandler).Name("getUser")
+		r.Post("/users", PingHandler).Name("users.create").With(option.OperationID("createUser"))
+		r.Delete("/users/:id", PingHandler)
+
+		require.NoError(t, r.Validate())
+		assert.Equal(t, map[string]string{
+			"get /users":            "listUsers",
+			"get /users/{id}/{tab}": "getUser",
+			"get /users/{id}":       "getUserWithoutTab",
+			"post /users":           "createUser",
+			"delete /users/{id}":    "",
+		}, operationIDs(t, r))
+	})
+	t.Run("disabled", func(t *testing.T) {
+		r := fiberopenapi.NewRouter(fiber.New())
+		r.Get("/users", PingHandler).Name("listUsers")
+
+		assert.Equal(t, map[string]string{"get /users": ""}, operationIDs(t, r))
+	})
+	t.Run("fallback", func(t *testing.T) {
+		r := fiberopenapi.NewRouterWithConfig(fiber.New(), fiberopenapi.Config{
+			NameOperationIDs: true,
+			OperationID:      fiberopenapi.DefaultOperationID,
+		})
+		r.Get("/users", PingHandler).Name("listUsers")
+		r.Get("/items/:id?", PingHandler)
+
+		require.NoError(t, r.Validate())
+		assert.Equal(t, map[string]string{
+			"get /users":      "listUsers",
+			"get /items/{id}": "getItemsById",
+			"get /items":      "getItems",
+		}, operationIDs(t, r))
+	})
+	t.Run("duplicates", func(t *testing.T) {
+		r := fiberopenapi.NewRouterWithConfig(fiber.New(), fiberopenapi.Config{
+			NameOperationIDs: true,
+			OperationID:      fiberopenapi.DefaultOperationID,
+		})
+		r.Get("/users", PingHandler).Name("getUsers")
+		r.Get("/users/", PingHandler).Name("getUsers")
+		r.Get("/accounts", PingHandler).Name("getUsersById")
+		r.Get("/users/:id", PingHandler)
+		r.Get("/hidden", PingHandler).Name("getUsers").With(option.Hide())
+
+		err := r.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `duplicate operationId "getUsers": GET /users and GET /users/`)
+		assert.Contains(t, err.Error(), `duplicate operationId "getUsersById": GET /accounts and GET /users/{id}`)
+		assert.NotContains(t, err.Error(), "/hidden")
+	})
+}
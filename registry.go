@@ -0,0 +1,561 @@
+package fiberopenapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi/internal/constant"
+	"github.com/oaswrap/fiberopenapi/internal/util"
+	"github.com/oaswrap/spec"
+	"github.com/oaswrap/spec/option"
+)
+
+// registry records the routes documented through a router tree and builds
+// the OpenAPI specification from them.
+//
+// The spec generator builds its document only once, so the registry keeps
+// its own copy of the routes and replays them into a new generator whenever
+// the version changed since the last build.
+type registry struct {
+	mu      sync.Mutex
+	opts    []option.OpenAPIOption
+	root    *group
+	version uint64
+	mounted []*registry // registries of the mounted generators
+	ids     operationIDs
+
+	docsHandlers map[uintptr]bool    // handlers of the docs routes, see Audit
+	security     *middlewareSecurity // see Config.SecurityMiddleware
+
+	source          SourceLocation // location of the router creation
+	sourceExtension bool
+	mockResponses   bool
+	injectExamples  bool
+
+	doc      *document
+	docBuilt uint64
+}
+
+// group is a node of the route tree, created by Group and Route.
+type group struct {
+	prefix string // full Fiber path prefix of the group
+	parent *group
+	opts   []option.GroupOption
+	routes []*route
+	groups []*group
+	mounts []*mount
+
+	// used holds the security of the middleware added with Use, which
+	// applies to the routes registered afterwards in the group and below.
+	used []option.OperationOption
+}
+
+// usedSecurity returns the security of the middleware added with Use to the
+// group and its parents.
+func (g *group) usedSecurity() []option.OperationOption {
+	var opts []option.OperationOption
+	for ; g != nil; g = g.parent {
+		opts = append(opts, g.used...)
+	}
+	return opts
+}
+
+func newRegistry(opts []option.OpenAPIOption) *registry {
+	return &registry{
+		opts:         opts,
+		root:         &group{},
+		docsHandlers: make(map[uintptr]bool),
+	}
+}
+
+// update applies fn to the route tree and invalidates the built specification.
+func (r *registry) update(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fn()
+	r.version++
+}
+
+// Version returns the current version of the specification.
+//
+// It changes every time a route or an option is added, to the router or to
+// one of the mounted generators.
+func (r *registry) Version() uint64 {
+	r.mu.Lock()
+	version, mounted := r.version, r.mounted
+	r.mu.Unlock()
+
+	for _, m := range mounted {
+		version += m.Version()
+	}
+	return version
+}
+
+// mounts reports whether target is r or is mounted on r, directly or through
+// the mounted generators.
+func (r *registry) mounts(target *registry) bool {
+	if r == target {
+		return true
+	}
+	r.mu.Lock()
+	mounted := r.mounted
+	r.mu.Unlock()
+
+	for _, m := range mounted {
+		if m.mounts(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// document returns the specification for the current version of the route tree.
+func (r *registry) document() *document {
+	version := r.Version()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.doc != nil && r.docBuilt == version {
+		return r.doc
+	}
+
+	doc := &document{
+		Generator:    spec.NewGenerator(r.opts...),
+		opts:         r.opts,
+		source:       r.source,
+		ids:          r.ids,
+		operationIDs: make(map[string]string),
+	}
+	if r.sourceExtension {
+		doc.sources = make(map[string]SourceLocation)
+	}
+	doc.examples = r.injectExamples
+	doc.security = r.security
+	r.root.register(doc, doc.Generator, nil)
+	r.doc, r.docBuilt = doc, version
+
+	return doc
+}
+
+// register replays the group into the spec router.
+//
+// Paths are registered in full, the spec group only carries the group options
+// so that tags, security and visibility are inherited like in the spec package.
+// inherited holds the options of the parent groups.
+func (g *group) register(doc *document, sr spec.Router, inherited []option.GroupOption) {
+	sg := sr.Group("/", append([]option.GroupOption{}, g.opts...)...)
+	inherited = append(append([]option.GroupOption{}, inherited...), g.opts...)
+	for _, rt := range g.routes {
+		rt.register(doc, sg, inherited)
+	}
+	for _, child := range g.groups {
+		child.register(doc, sg, inherited)
+	}
+	for _, m := range g.mounts {
+		doc.mounts = append(doc.mounts, mountedSpec{mount: m, groupOpts: inherited})
+	}
+}
+
+// register adds one operation per path variant of the route.
+func (r *route) register(doc *document, sr spec.Router, groupOpts []option.GroupOption) {
+	if r.err != nil {
+		doc.errs = append(doc.errs, r.sourceError(r.err))
+		return
+	}
+	opts := append([]option.OperationOption{}, r.opts...)
+
+	// OpenAPI has no CONNECT operation, so a CONNECT route is left out of
+	// the specification, and reported by Validate unless it is hidden.
+	if r.method == fiber.MethodConnect {
+		if err := r.unsupportedMethod(groupOpts); err != nil {
+			doc.omitted = append(doc.omitted, r.sourceError(err))
+		}
+		return
+	}
+
+	cfg := &option.OperationConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	hidden := isHidden(opts, groupOpts)
+
+	paths := util.ParsePath(r.path)
+	for _, p := range paths {
+		id := doc.ids.operationID(cfg.OperationID, r.name, r.method, p, paths[0])
+		if id != "" && !hidden {
+			doc.addOperationID(r, id, p.Template)
+		}
+		if doc.sources != nil && !hidden {
+			doc.sources[sourceKey(r.method, p.Template)] = r.source
+		}
+		op := operation(opts, p.Params, id)
+		sr.Add(r.method, p.Template, op)
+		doc.ops = append(doc.ops, addedOperation{
+			method:    r.method,
+			path:      p.Template,
+			opt:       op,
+			groupOpts: groupOpts,
+			at:        *r.sourceError(nil),
+		})
+	}
+}
+
+// unsupportedMethod returns the error reported for a route whose method
+// OpenAPI can not document, nil if the route is hidden.
+func (r *route) unsupportedMethod(groupOpts []option.GroupOption) error {
+	if r.method != fiber.MethodConnect || isHidden(r.opts, groupOpts) {
+		return nil
+	}
+	return fmt.Errorf("%s %s: OpenAPI does not support the CONNECT method, hide the route with option.Hide()", r.method, r.path)
+}
+
+// isHidden reports whether the operation options or its group options hide it.
+func isHidden(opts []option.OperationOption, groupOpts []option.GroupOption) bool {
+	groupCfg := &option.GroupConfig{}
+	for _, opt := range groupOpts {
+		opt(groupCfg)
+	}
+	cfg := &option.OperationConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return groupCfg.Hide || cfg.Hide
+}
+
+// operation returns the option that builds the operation for a path variant.
+//
+// It applies the route options, sets the operationId of the variant and
+// declares the path parameters of the variant that no request structure declares.
+func operation(opts []option.OperationOption, params []util.Param, id string) option.OperationOption {
+	return func(cfg *option.OperationConfig) {
+		for _, opt := range opts {
+			opt(cfg)
+		}
+		cfg.OperationID = id
+
+		structures := make([]any, 0, len(cfg.Requests))
+		for _, req := range cfg.Requests {
+			structures = append(structures, req.Structure)
+		}
+		declared := util.PathParamNames(structures...)
+
+		var missing []util.Param
+		for _, p := range params {
+			if !declared[p.Name] {
+				missing = append(missing, p)
+			}
+		}
+		if s := util.PathParamsStruct(missing); s != nil {
+			cfg.Requests = append(cfg.Requests, &option.ContentConfig{Structure: s})
+		}
+	}
+}
+
+// document is the specification built for a version of the route tree.
+//
+// It reports the errors found while registering the routes along with the
+// errors of the spec generator.
+type document struct {
+	spec.Generator
+	errs    []error
+	omitted []error // routes left out of the specification, reported by Validate only
+	mounts  []mountedSpec
+
+	security *middlewareSecurity // reports the unmatched security middleware, see Validate
+
+	ids          operationIDs
+	operationIDs map[string]string // operation of each operationId
+
+	sources  map[string]SourceLocation // registrations of the operations, for the x-source extensions
+	examples bool                      // see Config.InjectExamples
+
+	// opts, source and ops locate the errors of the generator, see specErrors.
+	opts       []option.OpenAPIOption
+	source     SourceLocation
+	ops        []addedOperation
+	locateOnce sync.Once
+	located    []error
+
+	mergeOnce sync.Once
+	merged    mergedSpec
+	mergeErr  error
+
+	parseOnce sync.Once
+	parsed    map[string]any
+	parseErr  error
+}
+
+// addOperationID records the operationId of an operation of the route, reporting duplicates.
+func (d *document) addOperationID(r *route, id, path string) {
+	op := r.method + " " + path
+	if prev, dup := d.operationIDs[id]; dup {
+		d.errs = append(d.errs, r.sourceError(fmt.Errorf("duplicate operationId %q: %s and %s", id, prev, op)))
+		return
+	}
+	d.operationIDs[id] = op
+}
+
+// addedOperation is an operation added to the generator of a document.
+type addedOperation struct {
+	method    string
+	path      string
+	opt       option.OperationOption
+	groupOpts []option.GroupOption
+	at        SourceError // location of the route, copied as routes change
+}
+
+// sourceError returns err located at the registration of the operation.
+func (op addedOperation) sourceError(err error) error {
+	e := op.at
+	e.Err = err
+	return &e
+}
+
+// specErrors returns the errors of the spec generator, located at the
+// creation of the router or at the registration of the routes causing them.
+//
+// The generator reflects all the operations at once, so its errors are
+// located by generating the specification of the router options alone, then
+// of each operation alone. The errors of several operations are located at
+// the last operation they name.
+func (d *document) specErrors() []error {
+	d.locateOnce.Do(func() {
+		errs := generatorErrors(d.Generator)
+		if len(errs) == 0 {
+			return
+		}
+		remaining := make(map[string]int, len(errs))
+		for _, err := range errs {
+			remaining[err.Error()]++
+		}
+		take := func(err error) bool {
+			if remaining[err.Error()] == 0 {
+				return false
+			}
+			remaining[err.Error()]--
+			return true
+		}
+
+		global := generatorErrors(spec.NewGenerator(d.opts...))
+		for _, err := range global {
+			if take(err) {
+				d.located = append(d.located, &SourceError{Source: d.source, Err: err})
+			}
+		}
+		// The operations are not reflected when the router options are invalid.
+		if len(global) == 0 {
+			for _, op := range d.ops {
+				gen := spec.NewGenerator(d.opts...)
+				gen.Group("/", op.groupOpts...).Add(op.method, op.path, op.opt)
+				for _, err := range generatorErrors(gen) {
+					if !take(err) {
+						continue
+					}
+					if !strings.Contains(err.Error(), sourceKey(op.method, op.path)) {
+						err = fmt.Errorf("%s %s: %w", op.method, op.path, err)
+					}
+					d.located = append(d.located, op.sourceError(err))
+				}
+			}
+		}
+		for _, err := range errs {
+			if take(err) {
+				d.located = append(d.located, d.locateConflict(err))
+			}
+		}
+	})
+	return d.located
+}
+
+// locateConflict locates an error of several operations, such as
+// "operation already exists: get /users", at the last operation it names.
+func (d *document) locateConflict(err error) error {
+	for i := len(d.ops) - 1; i >= 0; i-- {
+		op := d.ops[i]
+		if strings.HasSuffix(err.Error(), " "+sourceKey(op.method, op.path)) {
+			return op.sourceError(err)
+		}
+	}
+	return err
+}
+
+// generatorErrors returns the errors of a spec generator.
+func generatorErrors(gen spec.Generator) []error {
+	err := gen.Validate()
+	if err == nil {
+		return nil
+	}
+	if se, ok := err.(interface{ Errors() []error }); ok {
+		return se.Errors()
+	}
+	return []error{err}
+}
+
+// Validate checks for errors in the registered routes and the specification.
+//
+// The errors caused by a route or by the router options are *SourceError
+// errors, located at the registration of the route or the creation of the router.
+//
+// The routes that OpenAPI can not document, such as CONNECT routes, are
+// reported as well, but they are left out of the specification instead of
+// failing its generation.
+func (d *document) Validate() error {
+	errs := append(d.generationErrors(), d.omittedErrors()...)
+	return errors.Join(errs...)
+}
+
+// omittedErrors returns the errors of the routes and security middleware
+// left out of the specification, mounted generators included.
+func (d *document) omittedErrors() []error {
+	errs := append([]error{}, d.omitted...)
+	if d.security != nil {
+		errs = append(errs, d.security.unmatchedErrors(d.source)...)
+	}
+	for _, m := range d.mounts {
+		if m.groupConfig().Hide {
+			continue
+		}
+		for _, err := range m.reg.document().omittedErrors() {
+			errs = append(errs, fmt.Errorf("mount %s: %w", m.prefix, err))
+		}
+	}
+	return errs
+}
+
+// generationErrors returns the errors preventing the generation of the specification.
+func (d *document) generationErrors() []error {
+	errs := append([]error{}, d.errs...)
+	if specErrs := d.specErrors(); len(specErrs) > 0 {
+		errs = append(errs, specErrs...)
+	} else if _, err := d.merge(); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// validateGeneration reports the errors preventing the generation of the specification.
+func (d *document) validateGeneration() error {
+	return errors.Join(d.generationErrors()...)
+}
+
+// rewritten reports whether the specification of the generator is rewritten
+// to merge the mounted generators, or to add the x-source extensions or the
+// examples.
+func (d *document) rewritten() bool {
+	return len(d.mounts) > 0 || d.sources != nil || d.examples
+}
+
+// rewrite adds the x-source extensions and the examples to the specification.
+func (d *document) rewrite(doc map[string]any) {
+	addSourceExtensions(doc, d.sources)
+	if d.examples {
+		addExamples(doc)
+	}
+}
+
+// GenerateSchema generates the OpenAPI schema in the specified format.
+func (d *document) GenerateSchema(formats ...string) ([]byte, error) {
+	if err := d.validateGeneration(); err != nil {
+		return nil, err
+	}
+	if !d.rewritten() {
+		return d.Generator.GenerateSchema(formats...)
+	}
+	format := constant.FormatYAML
+	if len(formats) > 0 {
+		format = formats[0]
+	}
+	switch format {
+	case constant.FormatJSON:
+		return d.MarshalJSON()
+	case constant.FormatYAML, "yml":
+		return d.MarshalYAML()
+	}
+	return nil, fmt.Errorf("unsupported format: %s, expected 'json', 'yaml', or 'yml'", format)
+}
+
+// MarshalYAML marshals the OpenAPI schema to YAML format.
+func (d *document) MarshalYAML() ([]byte, error) {
+	if err := d.validateGeneration(); err != nil {
+		return nil, err
+	}
+	if !d.rewritten() {
+		return d.Generator.MarshalYAML()
+	}
+	merged, _ := d.merge()
+	return merged.MarshalYAML()
+}
+
+// MarshalJSON marshals the OpenAPI schema to JSON format.
+func (d *document) MarshalJSON() ([]byte, error) {
+	if err := d.validateGeneration(); err != nil {
+		return nil, err
+	}
+	if !d.rewritten() {
+		return d.Generator.MarshalJSON()
+	}
+	merged, _ := d.merge()
+	schema, err := merged.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := json.Indent(&buffer, schema, "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to indent OpenAPI JSON schema: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// WriteSchemaTo writes the OpenAPI schema to a file.
+func (d *document) WriteSchemaTo(path string) error {
+	if err := d.validateGeneration(); err != nil {
+		return err
+	}
+	if !d.rewritten() {
+		return d.Generator.WriteSchemaTo(path)
+	}
+	format := constant.FormatYAML
+	if strings.HasSuffix(path, ".json") {
+		format = constant.FormatJSON
+	} else if !strings.HasSuffix(path, ".yaml") && !strings.HasSuffix(path, ".yml") {
+		return fmt.Errorf("unsupported file extension: %s, expected '.json' or '.yaml' or '.yml'", path)
+	}
+	schema, err := d.GenerateSchema(format)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, schema, 0644)
+}
+
+// parsedSpec returns the specification parsed from JSON, once.
+func (d *document) parsedSpec() (map[string]any, error) {
+	d.parseOnce.Do(func() {
+		schema, err := d.MarshalJSON()
+		if err != nil {
+			d.parseErr = err
+			return
+		}
+		d.parseErr = json.Unmarshal(schema, &d.parsed)
+	})
+	return d.parsed, d.parseErr
+}
+
+// merge merges the specifications of the mounted generators into the one of
+// the document and rewrites it, once.
+func (d *document) merge() (mergedSpec, error) {
+	if !d.rewritten() {
+		return nil, nil
+	}
+	d.mergeOnce.Do(func() {
+		d.merged, d.mergeErr = mergeMounts(d.Generator, d.mounts, d.rewrite)
+	})
+	return d.merged, d.mergeErr
+}
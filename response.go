@@ -0,0 +1,153 @@
+package fiberopenapi
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi/internal/binding"
+	"github.com/oaswrap/spec/option"
+)
+
+// ResponseError reports a response that does not match the responses
+// declared for its route with option.Response.
+type ResponseError struct {
+	Method string
+	Path   string // Fiber path of the route
+	Status int
+	Detail string
+	Errors []FieldError
+}
+
+func (e *ResponseError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s: response %d %s", e.Method, e.Path, e.Status, e.Detail)
+	for i, fe := range e.Errors {
+		if i == 0 {
+			sb.WriteString(": ")
+		} else {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(fe.In)
+		if fe.Field != "" {
+			sb.WriteString(" " + fe.Field)
+		}
+		sb.WriteString(" " + fe.Message)
+	}
+	return sb.String()
+}
+
+// ResponseValidationHandler handles a response that fails response validation.
+//
+// Returning an error replaces the response, the error is passed to the
+// Fiber error handler.
+type ResponseValidationHandler func(c *fiber.Ctx, err *ResponseError) error
+
+// LogInvalidResponse logs the responses that fail validation and sends them unchanged.
+func LogInvalidResponse(_ *fiber.Ctx, err *ResponseError) error {
+	log.Printf("fiberopenapi: %v", err)
+	return nil
+}
+
+// FailInvalidResponse replaces the responses that fail validation with the
+// error, which the default Fiber error handler answers with a 500 status.
+func FailInvalidResponse(_ *fiber.Ctx, err *ResponseError) error {
+	return err
+}
+
+// responseValidator validates the responses of the routes when
+// Config.ValidateResponses is enabled.
+type responseValidator struct {
+	binder  *binding.Binder
+	handler ResponseValidationHandler
+	enabled bool
+}
+
+// validateResponse is the handler validating the response of the route handlers.
+//
+// A handler returning an error, such as fiber.ErrNotFound, has its response
+// written by the error handler of the app first, so that the status and the
+// body sent for the error are validated as well.
+func (r *route) validateResponse(c *fiber.Ctx) error {
+	if err := c.Next(); err != nil {
+		if err := c.App().ErrorHandler(c, err); err != nil {
+			_ = c.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+	if err := r.checkResponse(c); err != nil {
+		return r.validator.handler(c, err)
+	}
+	return nil
+}
+
+// checkResponse checks the response against the declared responses.
+func (r *route) checkResponse(c *fiber.Ctx) *ResponseError {
+	responses := r.config().Responses
+	if len(responses) == 0 {
+		return nil
+	}
+
+	status := c.Response().StatusCode()
+	fail := func(errs []FieldError, format string, args ...any) *ResponseError {
+		return &ResponseError{
+			Method: r.method,
+			Path:   r.path,
+			Status: status,
+			Detail: fmt.Sprintf(format, args...),
+			Errors: errs,
+		}
+	}
+
+	var declared []*option.ContentConfig
+	for _, resp := range responses {
+		if resp.HTTPStatus == status {
+			declared = append(declared, resp)
+		}
+	}
+	if len(declared) == 0 {
+		return fail(nil, "status is not declared")
+	}
+
+	body := c.Response().Body()
+	if c.Method() == fiber.MethodHead || len(body) == 0 {
+		return nil
+	}
+
+	contentType := string(c.Response().Header.ContentType())
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	contentType = strings.TrimSpace(contentType)
+
+	for _, resp := range declared {
+		if resp.Structure == nil {
+			continue
+		}
+		declaredType := resp.ContentType
+		if declaredType == "" {
+			declaredType = fiber.MIMEApplicationJSON
+		}
+		if !strings.EqualFold(declaredType, contentType) {
+			continue
+		}
+		if !strings.HasSuffix(contentType, "json") {
+			return nil
+		}
+		errs, err := r.validator.binder.ValidateJSON(resp.Structure, binding.InBody, body)
+		if err != nil {
+			return fail(nil, "body is invalid: %v", err)
+		}
+		if len(errs) > 0 {
+			return fail(errs, "body does not match the schema")
+		}
+		return nil
+	}
+
+	for _, resp := range declared {
+		if resp.Structure != nil {
+			return fail(nil, "content type %q is not declared", contentType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package fiberopenapi_test
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi"
+	"github.com/oaswrap/spec/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type PetResponse struct {
+	ID   int    `json:"id" required:"true"`
+	Name string `json:"name" minLength:"1"`
+}
+
+func TestRouter_ValidateResponses(t *testing.T) {
+	var invalid []*fiberopenapi.ResponseError
+	app := fiber.New()
+	r := fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{
+		ValidateResponses: true,
+		ResponseValidationHandler: func(c *fiber.Ctx, err *fiberopenapi.ResponseError) error {
+			invalid = append(invalid, err)
+			return fiberopenapi.FailInvalidResponse(c, err)
+		},
+	})
+
+	pets := r.Group("/pets")
+	pets.Get("/:id", func(c *fiber.Ctx) error {
+		switch c.Params("id") {
+		case "1":
+			return c.JSON(fiber.Map{"id": 1, "name": "Rex"})
+		case "2":
+			return c.JSON(fiber.Map{"name": ""})
+		case "3":
+			return c.Status(fiber.StatusTeapot).SendString("teapot")
+		case "4":
+			return c.SendString("plain text")
+		case "5":
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"message": "not found"})
+		case "6":
+			return fiber.ErrNotFound
+		}
+		return fiber.ErrTeapot
+	}).With(
+		option.Response(200, new(PetResponse)),
+		option.Response(404, nil),
+	)
+	pets.Get("/", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusAccepted).SendString("anything goes")
+	})
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+		err    string
+	}{
+		{name: "valid response", target: "/pets/1", status: http.StatusOK},
+		{name: "response without structure", target: "/pets/5", status: http.StatusNotFound},
+		{name: "declared handler error", target: "/pets/6", status: http.StatusNotFound},
+		{name: "route without responses", target: "/pets/", status: http.StatusAccepted},
+		{
+			name:   "body does not match",
+			target: "/pets/2",
+			status: http.StatusInternalServerError,
+			err:    "GET /pets/:id: response 200 body does not match the schema: body id is required; body name must be at least 1 characters long",
+		},
+		{
+			name:   "undeclared status",
+			target: "/pets/3",
+			status: http.StatusInternalServerError,
+			err:    "GET /pets/:id: response 418 status is not declared",
+		},
+		{
+			name:   "undeclared handler error",
+			target: "/pets/9",
+			status: http.StatusInternalServerError,
+			err:    "GET /pets/:id: response 418 status is not declared",
+		},
+		{
+			name:   "undeclared content type",
+			target: "/pets/4",
+			status: http.StatusInternalServerError,
+			err:    `GET /pets/:id: response 200 content type "text/plain" is not declared`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalid = nil
+			req, _ := http.NewRequest(http.MethodGet, tt.target, nil)
+			res, err := app.Test(req, -1)
+			require.NoError(t, err)
+			body, _ := io.ReadAll(res.Body)
+			_ = res.Body.Close()
+
+			assert.Equal(t, tt.status, res.StatusCode, string(body))
+			if tt.err == "" {
+				assert.Empty(t, invalid)
+				return
+			}
+			require.Len(t, invalid, 1)
+			assert.EqualError(t, invalid[0], tt.err)
+			assert.Equal(t, tt.err, string(body))
+		})
+	}
+}
+
+func TestRouter_ValidateResponses_Log(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	app := fiber.New()
+	r := fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{ValidateResponses: true})
+	r.Get("/pets/:id", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": 1})
+	}).With(option.Response(200, new(PetResponse)))
+
+	req, _ := http.NewRequest(http.MethodGet, "/pets/1", nil)
+	res, err := app.Test(req, -1)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, res.StatusCode, "expected the response to be sent unchanged")
+	assert.Contains(t, buf.String(), "fiberopenapi: GET /pets/:id: response 201 status is not declared")
+}
@@ -2,7 +2,6 @@ package fiberopenapi
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"github.com/oaswrap/spec"
 	"github.com/oaswrap/spec/option"
 )
 
@@ -15,23 +14,74 @@ type Route interface {
 }
 
 type route struct {
-	fr fiber.Router
-	sr spec.Route
+	reg    *registry
+	fr     fiber.Router
+	method string
+	path   string // full Fiber path of the route
+	name   string
+	opts   []option.OperationOption
+	source SourceLocation   // location of the registration
+	with   []SourceLocation // locations of the With calls
+
+	err error // set for routes that can not be documented, reported by Validate
+
+	binding   *routeBinding      // nil unless the route binds its request structures
+	validator *responseValidator // nil unless the route validates its responses
+
+	cfg     *option.OperationConfig // see config
+	cfgOpts int
 }
 
 // Name sets the name for the route.
+//
+// It is the operationId of the route when Config.NameOperationIDs is set.
+// Routes imported from a Fiber app keep their Fiber name.
 func (r *route) Name(name string) Route {
-	r.fr.Name(name)
+	if r.fr != nil {
+		r.fr.Name(name)
+	}
+	r.reg.update(func() {
+		r.name = name
+	})
 
 	return r
 }
 
 // With applies the given options to the route.
 func (r *route) With(opts ...option.OperationOption) Route {
-	if r.sr == nil {
-		return r
-	}
-	r.sr.With(opts...)
+	source := callerLocation()
+	r.reg.update(func() {
+		r.opts = append(r.opts, opts...)
+		r.with = append(r.with, source)
+	})
 
 	return r
 }
+
+// sourceError returns err located at the registration of the route.
+func (r *route) sourceError(err error) *SourceError {
+	var with []SourceLocation
+	for _, l := range r.with {
+		if l != r.source {
+			with = append(with, l)
+		}
+	}
+	return &SourceError{Source: r.source, With: with, Err: err}
+}
+
+// config returns the operation configuration built from the route options.
+//
+// It is used while serving requests, and rebuilt only when options are added.
+func (r *route) config() *option.OperationConfig {
+	r.reg.mu.Lock()
+	defer r.reg.mu.Unlock()
+
+	if r.cfg == nil || r.cfgOpts != len(r.opts) {
+		cfg := &option.OperationConfig{}
+		for _, opt := range r.opts {
+			opt(cfg)
+		}
+		r.cfg, r.cfgOpts = cfg, len(r.opts)
+	}
+	return r.cfg
+}
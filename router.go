@@ -2,14 +2,17 @@ package fiberopenapi
 
 import (
 	stdpath "path"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/oaswrap/fiberopenapi/internal/binding"
 	"github.com/oaswrap/fiberopenapi/internal/constant"
 	"github.com/oaswrap/fiberopenapi/internal/handler"
-	"github.com/oaswrap/fiberopenapi/internal/util"
-	"github.com/oaswrap/spec"
 	"github.com/oaswrap/spec/openapi"
 	"github.com/oaswrap/spec/option"
+	"github.com/swaggest/jsonschema-go"
+	oapi "github.com/swaggest/openapi-go"
 )
 
 // NewGenerator creates a new OpenAPI generator with the specified Fiber router and options.
@@ -23,6 +26,14 @@ func NewGenerator(r fiber.Router, opts ...option.OpenAPIOption) Generator {
 //
 // It initializes the OpenAPI generator and sets up the necessary routes for OpenAPI documentation.
 func NewRouter(r fiber.Router, opts ...option.OpenAPIOption) Generator {
+	return NewRouterWithConfig(r, Config{}, opts...)
+}
+
+// NewRouterWithConfig creates a new OpenAPI router with the specified Fiber router,
+// router configuration and options.
+//
+// It initializes the OpenAPI generator and sets up the necessary routes for OpenAPI documentation.
+func NewRouterWithConfig(r fiber.Router, config Config, opts ...option.OpenAPIOption) Generator {
 	defaultOpts := []option.OpenAPIOption{
 		option.WithTitle(constant.DefaultTitle),
 		option.WithDescription(constant.DefaultDescription),
@@ -31,13 +42,39 @@ func NewRouter(r fiber.Router, opts ...option.OpenAPIOption) Generator {
 		option.WithSwaggerConfig(openapi.SwaggerConfig{}),
 	}
 	opts = append(defaultOpts, opts...)
-	gen := spec.NewGenerator(opts...)
-	cfg := gen.Config()
+	opts = append(opts, withOptionalPathParams())
+	reg := newRegistry(opts)
+	reg.ids = operationIDs{fromName: config.NameOperationIDs, fallback: config.OperationID}
+	reg.source = callerLocation()
+	reg.sourceExtension = config.SourceExtension
+	reg.mockResponses = config.MockResponses
+	reg.injectExamples = config.InjectExamples
+	cfg := option.WithOpenAPIConfig(opts...)
 
 	rr := &router{
 		fiberRouter: r,
-		specRouter:  gen,
-		gen:         gen,
+		reg:         reg,
+		group:       reg.root,
+	}
+	rr.root = rr
+	rr.security = newMiddlewareSecurity(config.SecurityMiddleware)
+	reg.security = rr.security
+	binder := binding.New(cfg.ReflectorConfig)
+	rr.binder = &requestBinder{
+		binder:       binder,
+		errorHandler: config.RequestErrorHandler,
+		enabled:      config.BindRequests,
+	}
+	if rr.binder.errorHandler == nil {
+		rr.binder.errorHandler = DefaultRequestErrorHandler
+	}
+	rr.validator = &responseValidator{
+		binder:  binder,
+		handler: config.ResponseValidationHandler,
+		enabled: config.ValidateResponses,
+	}
+	if rr.validator.handler == nil {
+		rr.validator.handler = LogInvalidResponse
 	}
 
 	// If docs are disabled, return the router without adding docs routes.
@@ -45,23 +82,110 @@ func NewRouter(r fiber.Router, opts ...option.OpenAPIOption) Generator {
 		return rr
 	}
 
-	handler := handler.NewOpenAPIHandler(cfg, gen)
-	openapiPath := stdpath.Join(cfg.DocsPath, constant.OpenAPIFileName)
+	handlerOpts := []handler.Option{
+		handler.WithSpecFormat(config.SpecFormat),
+		handler.WithUI(config.DocsUI),
+		handler.WithCSPNonce(config.DocsCSPNonce),
+		handler.WithCacheControl(config.SpecCacheControl),
+	}
+	if config.SpecView != nil {
+		handlerOpts = append(handlerOpts, handler.WithView(rr.specView(config.SpecView)))
+	}
+	handler := handler.NewOpenAPIHandler(cfg, rr, handlerOpts...)
 
-	r.Get(cfg.DocsPath, handler.Docs)
-	r.Get(openapiPath, handler.OpenAPIYaml)
+	docs := config.DocsRouter
+	if docs == nil {
+		docs = r
+	}
+	get := func(path string, h ...fiber.Handler) {
+		handlers := append(append([]fiber.Handler{}, config.DocsMiddleware...), h...)
+		docs.Get(path, handlers...)
+		reg.docsHandlers[handlerID(h[len(h)-1])] = true
+	}
+	var specMiddleware []fiber.Handler
+	if config.CompressSpec {
+		specMiddleware = append(specMiddleware, compressSpec())
+	}
+	getSpec := func(path string, h fiber.Handler) {
+		get(path, append(specMiddleware, h)...)
+	}
+	get(cfg.DocsPath, handler.Docs)
+	getSpec(stdpath.Join(cfg.DocsPath, constant.OpenAPIFileName), handler.OpenAPIYaml)
+	getSpec(stdpath.Join(cfg.DocsPath, constant.OpenAPIJSONFileName), handler.OpenAPIJson)
+	getSpec(stdpath.Join(cfg.DocsPath, constant.OpenAPISpecName), handler.OpenAPI)
+	// Each asset has its own route, so that DocsMiddleware does not run for
+	// the routes registered later under the docs path.
+	for _, asset := range handler.Assets() {
+		get(stdpath.Join(cfg.DocsPath, asset), handler.DocsAssets)
+	}
 
 	return rr
 }
 
+// compressSpec compresses the specification with the Fiber compress middleware.
+//
+// The ETag of a compressed specification is made weak, as it identifies the
+// content of the specification and not the bytes of each encoding.
+func compressSpec() fiber.Handler {
+	compressor := compress.New()
+	return func(c *fiber.Ctx) error {
+		if err := compressor(c); err != nil {
+			return err
+		}
+		etag := c.GetRespHeader(fiber.HeaderETag)
+		if etag != "" && c.GetRespHeader(fiber.HeaderContentEncoding) != "" && !strings.HasPrefix(etag, "W/") {
+			c.Set(fiber.HeaderETag, "W/"+etag)
+		}
+		return nil
+	}
+}
+
+// withOptionalPathParams drops path parameters that are not part of the
+// operation path from the request structures.
+//
+// A route with optional parameters is documented once per path variant, and
+// the variants without the parameter share the request structure that declares it.
+func withOptionalPathParams() option.OpenAPIOption {
+	return func(cfg *openapi.Config) {
+		if cfg.ReflectorConfig == nil {
+			cfg.ReflectorConfig = &openapi.ReflectorConfig{}
+		}
+		next := cfg.ReflectorConfig.InterceptPropFunc
+		cfg.ReflectorConfig.InterceptPropFunc = func(params openapi.InterceptPropParams) error {
+			if oc, ok := oapi.OperationCtx(params.Context); ok && oc.ProcessingIn() == oapi.InPath && len(params.Path) <= 1 {
+				if !strings.Contains(oc.PathPattern(), "{"+params.Name+"}") {
+					return jsonschema.ErrSkipProperty
+				}
+			}
+			if next != nil {
+				return next(params)
+			}
+			return nil
+		}
+	}
+}
+
+var _ Generator = (*router)(nil)
+
+// router implements Generator for the root router and every sub-router,
+// sub-routers generate the specification of the whole router tree.
 type router struct {
 	fiberRouter fiber.Router
-	specRouter  spec.Router
-	gen         spec.Generator
+	reg         *registry
+	group       *group
+	root        *router
+	binder      *requestBinder
+	validator   *responseValidator
+	security    *middlewareSecurity
 }
 
 func (r *router) Use(args ...any) Router {
 	r.fiberRouter.Use(args...)
+	if opts := r.security.operationOptions(useHandlers(args)); len(opts) > 0 {
+		r.reg.update(func() {
+			r.group.used = append(r.group.used, opts...)
+		})
+	}
 	return r
 }
 
@@ -90,9 +214,7 @@ func (r *router) Delete(path string, handler ...fiber.Handler) Route {
 }
 
 func (r *router) Connect(path string, handler ...fiber.Handler) Route {
-	fr := r.fiberRouter.Connect(path, handler...)
-
-	return &route{fr: fr}
+	return r.Add(fiber.MethodConnect, path, handler...)
 }
 
 func (r *router) Options(path string, handler ...fiber.Handler) Route {
@@ -104,67 +226,126 @@ func (r *router) Trace(path string, handler ...fiber.Handler) Route {
 }
 
 func (r *router) Add(method, path string, handler ...fiber.Handler) Route {
-	fr := r.fiberRouter.Add(method, path, handler...)
-	sr := r.specRouter.Add(method, util.ConvertPath(path))
+	return r.add(method, path, r.binder.enabled, handler...)
+}
 
+// add registers a route, binding its request structures before the handlers if bind is set.
+func (r *router) add(method, path string, bind bool, handler ...fiber.Handler) *route {
 	route := &route{
-		fr: fr,
-		sr: sr,
+		reg:    r.reg,
+		method: method,
+		path:   r.group.prefix + path,
+		source: callerLocation(),
+	}
+	security := r.security.operationOptions(handler)
+	handler = r.mockHandlers(route, handler)
+	if r.validator.enabled {
+		route.validator = r.validator
+		handler = append([]fiber.Handler{route.validateResponse}, handler...)
 	}
+	if bind {
+		route.binding = newRouteBinding(r.binder, route.path)
+		handler = append([]fiber.Handler{route.bind}, handler...)
+	}
+	route.fr = r.fiberRouter.Add(method, path, handler...)
+	r.reg.update(func() {
+		route.opts = append(r.group.usedSecurity(), security...)
+		r.group.routes = append(r.group.routes, route)
+	})
 
 	return route
 }
 
+// mockHandlers replaces the handlers of the route with its mock when
+// Config.MockResponses is set, and MockHandler otherwise.
+func (r *router) mockHandlers(route *route, handlers []fiber.Handler) []fiber.Handler {
+	if r.reg.mockResponses {
+		return []fiber.Handler{route.mock}
+	}
+	mocked := make([]fiber.Handler, len(handlers))
+	for i, h := range handlers {
+		if isMockHandler(h) {
+			h = route.mock
+		}
+		mocked[i] = h
+	}
+	return mocked
+}
+
 func (r *router) Static(prefix, root string, config ...fiber.Static) Router {
 	r.fiberRouter.Static(prefix, root, config...)
 	return r
 }
 
 func (r *router) Group(prefix string, handlers ...fiber.Handler) Router {
-	rr := r.fiberRouter.Group(prefix, handlers...)
-	sr := r.specRouter.Group(prefix)
-
-	return &router{
-		fiberRouter: rr,
-		specRouter:  sr,
+	fr := r.fiberRouter.Group(prefix, handlers...)
+	sub := r.subRouter(fr, prefix)
+	if opts := r.security.groupOptions(handlers); len(opts) > 0 {
+		sub.With(opts...)
 	}
+
+	return sub
 }
 
 func (r *router) Route(prefix string, fn func(router Router)) Router {
 	fr := r.fiberRouter.Group(prefix)
-	sr := r.specRouter.Group(prefix)
-
-	subRouter := &router{
-		fiberRouter: fr,
-		specRouter:  sr,
-	}
+	subRouter := r.subRouter(fr, prefix)
 
 	fn(subRouter)
 
 	return subRouter
 }
 
+func (r *router) subRouter(fr fiber.Router, prefix string) *router {
+	g := &group{prefix: r.group.prefix + prefix, parent: r.group}
+	r.reg.update(func() {
+		r.group.groups = append(r.group.groups, g)
+	})
+
+	return &router{
+		fiberRouter: fr,
+		reg:         r.reg,
+		group:       g,
+		root:        r.root,
+		binder:      r.binder,
+		validator:   r.validator,
+		security:    r.security,
+	}
+}
+
 func (r *router) With(opts ...option.GroupOption) Router {
-	r.specRouter.Use(opts...)
+	r.reg.update(func() {
+		r.group.opts = append(r.group.opts, opts...)
+	})
 	return r
 }
 
+func (r *router) Generator() Generator {
+	return r.root
+}
+
+// SpecVersion returns the version of the specification, which changes every
+// time the routes or their options change.
+func (r *router) SpecVersion() uint64 {
+	return r.reg.Version()
+}
+
 func (r *router) Validate() error {
-	return r.gen.Validate()
+	return r.reg.document().Validate()
 }
 
 func (r *router) GenerateOpenAPISchema(formats ...string) ([]byte, error) {
-	return r.gen.GenerateSchema(formats...)
+	return r.reg.document().GenerateSchema(formats...)
 }
 
 func (r *router) MarshalYAML() ([]byte, error) {
-	return r.gen.MarshalYAML()
+	return r.reg.document().MarshalYAML()
 }
 
 func (r *router) MarshalJSON() ([]byte, error) {
-	return r.gen.MarshalJSON()
+	return r.reg.document().MarshalJSON()
 }
 
 func (r *router) WriteSchemaTo(path string) error {
-	return r.gen.WriteSchemaTo(path)
+	return r.reg.document().WriteSchemaTo(path)
 }
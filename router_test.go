@@ -1,13 +1,17 @@
 package fiberopenapi_test
 
 import (
+	"encoding/json"
 	"flag"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -160,6 +164,20 @@ type UpdatePetFormDataRequest struct {
 	Status string `formData:"status" enum:"available,pending,sold"`
 }
 
+type GetUserRequest struct {
+	ID     int64  `path:"id"`
+	Fields string `query:"fields"`
+}
+
+type GetFileRequest struct {
+	Path string `path:"wildcard"`
+}
+
+type GetFlightRequest struct {
+	From string `path:"from"`
+	To   string `path:"to"`
+}
+
 type UploadImageRequest struct {
 	ID                 int64           `params:"petId" path:"petId"`
 	AdditionalMetaData string          `query:"additionalMetadata"`
@@ -304,6 +322,47 @@ func TestRouter_Spec(t *testing.T) {
 				}).With(option.GroupTags("pet"), option.GroupSecurity("petstore_auth", "write:pets", "read:pets"))
 			},
 		},
+		{
+			name:   "Fiber Path Syntax",
+			golden: "fiber_path_syntax.yaml",
+			setup: func(r fiberopenapi.Router) {
+				r.Get("/users/:id<int>?", nil).With(
+					option.Summary("Get users or a single user"),
+					option.Request(new(GetUserRequest)),
+				)
+				r.Get("/files/*", nil).With(
+					option.Summary("Get a file"),
+					option.Request(new(GetFileRequest)),
+				)
+				r.Get("/flights/:from-:to", nil).With(
+					option.Summary("Get flights between airports"),
+					option.Request(new(GetFlightRequest)),
+				)
+			},
+		},
+		{
+			name:   "Path Params Inference",
+			golden: "path_params_inference.yaml",
+			setup: func(r fiberopenapi.Router) {
+				r.Get("/pets/:petId", nil).With(
+					option.Summary("Get a pet"),
+					option.Response(200, new(Pet)),
+				)
+				r.Get("/orders/:orderId<int;min(1)>/items/:sku<regex(^[A-Z]{3}-\\d+$)>", nil).With(
+					option.Summary("Get an order item"),
+				)
+				r.Delete("/pets/:petId", nil).With(
+					option.Summary("Delete a pet"),
+					option.Request(new(DeletePetRequest)),
+				)
+				r.Route("/stores/:storeId<guid>", func(r fiberopenapi.Router) {
+					r.Get("/pets/:petId", nil).With(
+						option.Summary("Get a pet of a store"),
+						option.Request(new(FindPetByIdRequest)),
+					)
+				})
+			},
+		},
 		{
 			name: "Invalid OpenAPI Version",
 			options: []option.OpenAPIOption{
@@ -407,6 +466,7 @@ func TestRouter_Fiber(t *testing.T) {
 	r.Connect("/ping", pingHandler).With(
 		option.Summary("Ping Endpoint with CONNECT"),
 		option.Description("Endpoint to test ping functionality with CONNECT method"),
+		option.Hide(), // OpenAPI has no CONNECT operation
 	).Name("ping.connect")
 	r.Trace("/ping", pingHandler).With(
 		option.Summary("Ping Endpoint with TRACE"),
@@ -472,6 +532,665 @@ func TestRouter_Fiber(t *testing.T) {
 		assert.NotEmpty(t, body, "expected non-empty response body for OpenAPI YAML route")
 		_ = res.Body.Close()
 	})
+	t.Run("must register OpenAPI JSON route", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/docs/openapi.json", nil)
+		res, err := app.Test(req, -1)
+		require.NoError(t, err, "failed to test OpenAPI JSON route")
+		assert.Equal(t, http.StatusOK, res.StatusCode, "expected status OK for OpenAPI JSON route")
+		assert.Equal(t, "application/json", res.Header.Get(fiber.HeaderContentType))
+		_ = res.Body.Close()
+	})
+	t.Run("must register negotiated OpenAPI route", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/docs/openapi", nil)
+		req.Header.Set(fiber.HeaderAccept, "application/json")
+		res, err := app.Test(req, -1)
+		require.NoError(t, err, "failed to test negotiated OpenAPI route")
+		assert.Equal(t, http.StatusOK, res.StatusCode, "expected status OK for negotiated OpenAPI route")
+		assert.Equal(t, "application/json", res.Header.Get(fiber.HeaderContentType))
+		_ = res.Body.Close()
+	})
+}
+
+func TestNewRouterWithConfig(t *testing.T) {
+	app := fiber.New()
+	r := fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{SpecFormat: "json"},
+		option.WithTitle("Test API JSON Spec Format"),
+	)
+	r.Get("/ping", PingHandler).With(option.Summary("Ping Endpoint"))
+
+	req, _ := http.NewRequest("GET", "/docs", nil)
+	res, err := app.Test(req, -1)
+	require.NoError(t, err, "failed to test docs route")
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err, "failed to read response body for docs route")
+	assert.Contains(t, string(body), "/docs/openapi.json", "expected docs UI to load the JSON spec")
+	_ = res.Body.Close()
+
+	req, _ = http.NewRequest("GET", "/docs/openapi", nil)
+	res, err = app.Test(req, -1)
+	require.NoError(t, err, "failed to test negotiated OpenAPI route")
+	assert.Equal(t, "application/json", res.Header.Get(fiber.HeaderContentType))
+	_ = res.Body.Close()
+}
+
+func TestRouter_DocsUI(t *testing.T) {
+	app := fiber.New()
+	fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{DocsUI: fiberopenapi.Redoc()},
+		option.WithDocsPath("/reference"),
+	)
+
+	req, _ := http.NewRequest("GET", "/reference", nil)
+	res, err := app.Test(req, -1)
+	require.NoError(t, err, "failed to test docs route")
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err, "failed to read response body for docs route")
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, string(body), `<redoc spec-url="/reference/openapi.yaml">`)
+}
+
+func TestRouter_EmbeddedDocsUI(t *testing.T) {
+	app := fiber.New()
+	r := fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{DocsUI: fiberopenapi.SwaggerUIEmbedded()},
+		option.WithDocsPath("/reference"),
+	)
+	r.Get("/ping", PingHandler).With(option.Summary("Ping Endpoint"))
+
+	get := func(t *testing.T, path string) (*http.Response, string) {
+		t.Helper()
+		req, _ := http.NewRequest("GET", path, nil)
+		res, err := app.Test(req, -1)
+		require.NoError(t, err, "failed to test %s", path)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err, "failed to read response body for %s", path)
+		_ = res.Body.Close()
+		return res, string(body)
+	}
+
+	res, page := get(t, "/reference")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	// Every resource of the page is served by the application itself.
+	refs := regexp.MustCompile(`(?:src|href)="([^"]*)"`).FindAllStringSubmatch(page, -1)
+	require.NotEmpty(t, refs, "expected the page to reference its assets")
+	for _, ref := range refs {
+		assert.True(t, strings.HasPrefix(ref[1], "/reference/"), "expected %q to be served under the docs path", ref[1])
+		res, _ := get(t, ref[1])
+		assert.Equal(t, http.StatusOK, res.StatusCode, "expected %q to be served", ref[1])
+	}
+	assert.NotContains(t, page, "cdn", "expected no CDN reference")
+
+	res, schema := get(t, "/reference/openapi.yaml")
+	assert.Equal(t, http.StatusOK, res.StatusCode, "expected the assets not to shadow the spec")
+	assert.Contains(t, schema, "/ping")
+
+	r.Get("/reference/changelog", func(c *fiber.Ctx) error {
+		return c.SendString("changelog")
+	})
+	res, body := get(t, "/reference/changelog")
+	assert.Equal(t, http.StatusOK, res.StatusCode, "expected the assets not to shadow the routes registered after them")
+	assert.Equal(t, "changelog", body)
+}
+
+func TestRouter_DocsMiddleware(t *testing.T) {
+	requireKey := func(c *fiber.Ctx) error {
+		if c.Get("X-API-Key") != "secret" {
+			return fiber.ErrUnauthorized
+		}
+		return c.Next()
+	}
+	status := func(t *testing.T, app *fiber.App, path, key string) int {
+		t.Helper()
+		req, _ := http.NewRequest("GET", path, nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		res, err := app.Test(req, -1)
+		require.NoError(t, err, "failed to test %s", path)
+		_ = res.Body.Close()
+		return res.StatusCode
+	}
+	docsPaths := []string{"/docs", "/docs/openapi.yaml", "/docs/openapi.json", "/docs/openapi"}
+
+	t.Run("same router", func(t *testing.T) {
+		app := fiber.New()
+		r := fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{
+			DocsMiddleware: []fiber.Handler{requireKey},
+		})
+		r.Get("/ping", PingHandler)
+
+		for _, path := range docsPaths {
+			assert.Equal(t, http.StatusUnauthorized, status(t, app, path, ""), "expected %s to require the key", path)
+			assert.Equal(t, http.StatusOK, status(t, app, path, "secret"), "expected %s to be served with the key", path)
+		}
+		assert.Equal(t, http.StatusOK, status(t, app, "/ping", ""), "expected the API routes to be left alone")
+	})
+	t.Run("admin router", func(t *testing.T) {
+		app, admin := fiber.New(), fiber.New()
+		r := fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{
+			DocsUI:         fiberopenapi.SwaggerUIEmbedded(),
+			DocsMiddleware: []fiber.Handler{requireKey},
+			DocsRouter:     admin,
+		})
+		r.Get("/ping", PingHandler)
+
+		for _, path := range append(docsPaths, "/docs/swagger-ui.css") {
+			assert.Equal(t, http.StatusNotFound, status(t, app, path, "secret"), "expected %s not to be served by the API app", path)
+			assert.Equal(t, http.StatusUnauthorized, status(t, admin, path, ""), "expected %s to require the key", path)
+			assert.Equal(t, http.StatusOK, status(t, admin, path, "secret"), "expected %s to be served by the admin app", path)
+		}
+		assert.Equal(t, http.StatusOK, status(t, app, "/ping", ""))
+	})
+	t.Run("routes under the docs path", func(t *testing.T) {
+		app := fiber.New()
+		r := fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{
+			DocsUI:         fiberopenapi.SwaggerUIEmbedded(),
+			DocsMiddleware: []fiber.Handler{requireKey},
+		})
+		r.Get("/docs/changelog", PingHandler)
+
+		assert.Equal(t, http.StatusUnauthorized, status(t, app, "/docs/swagger-ui.css", ""), "expected the assets to require the key")
+		assert.Equal(t, http.StatusOK, status(t, app, "/docs/swagger-ui.css", "secret"))
+		assert.Equal(t, http.StatusOK, status(t, app, "/docs/changelog", ""), "expected the later routes not to run the docs middleware")
+	})
+}
+
+func TestRouter_SpecCaching(t *testing.T) {
+	app := fiber.New()
+	r := fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{
+		SpecCacheControl: "public, max-age=300",
+		CompressSpec:     true,
+	})
+	r.Get("/ping", PingHandler).With(option.Summary("Ping Endpoint"))
+
+	get := func(t *testing.T, encoding, etag string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest("GET", "/docs/openapi.json", nil)
+		req.Header.Set(fiber.HeaderAcceptEncoding, encoding)
+		if etag != "" {
+			req.Header.Set(fiber.HeaderIfNoneMatch, etag)
+		}
+		res, err := app.Test(req, -1)
+		require.NoError(t, err, "failed to test OpenAPI JSON route")
+		return res
+	}
+
+	res := get(t, "identity", "")
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "public, max-age=300", res.Header.Get(fiber.HeaderCacheControl))
+	assert.Empty(t, res.Header.Get(fiber.HeaderContentEncoding))
+	etag := res.Header.Get(fiber.HeaderETag)
+	require.NotEmpty(t, etag)
+
+	for _, encoding := range []string{"gzip", "br"} {
+		t.Run(encoding, func(t *testing.T) {
+			res := get(t, encoding, "")
+			_ = res.Body.Close()
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, encoding, res.Header.Get(fiber.HeaderContentEncoding))
+			assert.Equal(t, "W/"+etag, res.Header.Get(fiber.HeaderETag))
+
+			res = get(t, encoding, res.Header.Get(fiber.HeaderETag))
+			_ = res.Body.Close()
+			assert.Equal(t, http.StatusNotModified, res.StatusCode)
+		})
+	}
+}
+
+func TestRouter_SecurityMiddleware(t *testing.T) {
+	auth := func(scope string) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			if c.Get("X-Scope") != scope {
+				return fiber.ErrUnauthorized
+			}
+			return c.Next()
+		}
+	}
+	read, write, logger := auth("read"), auth("write"), func(c *fiber.Ctx) error { return c.Next() }
+
+	app := fiber.New()
+	r := fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{
+		SecurityMiddleware: []fiberopenapi.SecurityMiddleware{
+			{Middleware: read, Name: "oauth", Scopes: []string{"pets:read"}},
+			{Middleware: write, Name: "oauth", Scopes: []string{"pets:write"}},
+		},
+	},
+		option.WithSecurity("oauth", option.SecurityOAuth2(openapi.OAuthFlows{
+			ClientCredentials: &openapi.OAuthFlowsDefsClientCredentials{
+				TokenURL: "https://auth.example.com/token",
+				Scopes:   map[string]string{"pets:read": "Read pets", "pets:write": "Write pets"},
+			},
+		})),
+	)
+	r.Use(logger)
+	r.Get("/health", PingHandler)
+
+	pets := r.Group("/pets", read)
+	pets.Get("/", PingHandler)
+	pets.Post("/", write, PingHandler)
+
+	admin := r.Group("/admin")
+	admin.Get("/status", PingHandler)
+	admin.Use("/secret", write)
+	admin.Get("/secret/key", PingHandler)
+	admin.Use(read)
+	admin.Get("/audit", PingHandler)
+
+	require.NoError(t, r.Validate())
+	schema, err := r.MarshalJSON()
+	require.NoError(t, err)
+
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Security []map[string][]string `json:"security"`
+		} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(schema, &doc))
+	security := func(path, method string) []map[string][]string {
+		op, ok := doc.Paths[path][method]
+		require.True(t, ok, "expected %s %s to be documented", method, path)
+		return op.Security
+	}
+
+	assert.Empty(t, security("/health", "get"), "expected a route without security middleware to be public")
+	assert.Equal(t, []map[string][]string{{"oauth": {"pets:read"}}}, security("/pets", "get"))
+	assert.Contains(t, security("/pets", "post"), map[string][]string{"oauth": {"pets:write"}})
+	assert.Empty(t, security("/admin/status", "get"), "expected Use to apply to the routes registered afterwards")
+	assert.Empty(t, security("/admin/secret/key", "get"), "expected Use with a prefix to be ignored")
+	assert.Equal(t, []map[string][]string{{"oauth": {"pets:read"}}}, security("/admin/audit", "get"))
+}
+
+type bearerAuth struct {
+	token string
+}
+
+func (a *bearerAuth) Handle(c *fiber.Ctx) error {
+	if c.Get(fiber.HeaderAuthorization) != "Bearer "+a.token {
+		return fiber.ErrUnauthorized
+	}
+	return c.Next()
+}
+
+func TestRouter_SecurityMiddlewareMethodValue(t *testing.T) {
+	auth := &bearerAuth{token: "secret"}
+	newRouter := func(mappings ...fiberopenapi.SecurityMiddleware) fiberopenapi.Generator {
+		return fiberopenapi.NewRouterWithConfig(fiber.New(), fiberopenapi.Config{SecurityMiddleware: mappings},
+			option.WithSecurity("bearerAuth", option.SecurityHTTPBearer("Bearer")),
+		)
+	}
+
+	t.Run("matches a method value evaluated again", func(t *testing.T) {
+		r := newRouter(fiberopenapi.SecurityMiddleware{Middleware: auth.Handle, Name: "bearerAuth"})
+		r.Group("/api", auth.Handle).Get("/pets", PingHandler)
+
+		require.NoError(t, r.Validate())
+		schema, err := r.MarshalYAML()
+		require.NoError(t, err)
+		assert.Contains(t, string(schema), "security:\n      - bearerAuth: []")
+	})
+
+	t.Run("reports unmatched middleware", func(t *testing.T) {
+		other := &bearerAuth{token: "other"}
+		r := newRouter(
+			fiberopenapi.SecurityMiddleware{Middleware: auth.Handle, Name: "bearerAuth"},
+			fiberopenapi.SecurityMiddleware{Middleware: other.Handle, Name: "bearerAuth"},
+		)
+		r.Group("/api", auth.Handle).Get("/pets", PingHandler)
+
+		err := r.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "security middleware 0 (bearerAuth) is not used by any route or group")
+		assert.Contains(t, err.Error(), "security middleware 1 (bearerAuth) is not used by any route or group",
+			"expected method values of different receivers not to be matched by their function")
+	})
+}
+
+func TestRouter_Mount(t *testing.T) {
+	type Invoice struct {
+		ID     string `json:"id"`
+		Amount int    `json:"amount"`
+	}
+	newBilling := func() (*fiber.App, fiberopenapi.Generator) {
+		app := fiber.New()
+		r := fiberopenapi.NewRouter(app,
+			option.WithDisableDocs(),
+			option.WithSecurity("apiKey", option.SecurityAPIKey("X-API-Key", openapi.SecuritySchemeAPIKeyInHeader)),
+			option.WithTags(openapi.Tag{Name: "Billing", Description: "Billing operations"}),
+		)
+		r.Get("/invoices", func(c *fiber.Ctx) error {
+			return c.JSON([]Invoice{{ID: "1", Amount: 42}})
+		}).With(
+			option.OperationID("listInvoices"),
+			option.Summary("List invoices"),
+			option.Tags("Billing"),
+			option.Security("apiKey"),
+			option.Response(200, new([]Invoice)),
+		)
+		return app, r
+	}
+	getJSON := func(t *testing.T, gen fiberopenapi.Generator) map[string]any {
+		t.Helper()
+		schema, err := gen.MarshalJSON()
+		require.NoError(t, err)
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(schema, &doc))
+		return doc
+	}
+
+	t.Run("merges the specification", func(t *testing.T) {
+		app := fiber.New()
+		r := fiberopenapi.NewRouter(app, option.WithTitle("Test API Mount"))
+		r.Get("/ping", PingHandler).With(option.Summary("Ping Endpoint"))
+		_, billing := newBilling()
+		r.Group("/api").With(option.GroupTags("API")).Mount("/billing", billing)
+
+		req, _ := http.NewRequest("GET", "/api/billing/invoices", nil)
+		res, err := app.Test(req, -1)
+		require.NoError(t, err, "failed to test mounted route")
+		assert.Equal(t, http.StatusOK, res.StatusCode, "expected the Fiber app to be mounted")
+		_ = res.Body.Close()
+
+		require.NoError(t, r.Validate())
+		doc := getJSON(t, r)
+		paths := doc["paths"].(map[string]any)
+		assert.Contains(t, paths, "/ping")
+		require.Contains(t, paths, "/api/billing/invoices")
+		op := paths["/api/billing/invoices"].(map[string]any)["get"].(map[string]any)
+		assert.Equal(t, []any{"Billing", "API"}, op["tags"])
+		assert.Equal(t, []any{map[string]any{"apiKey": []any{}}}, op["security"])
+
+		components := doc["components"].(map[string]any)
+		assert.Contains(t, components["securitySchemes"], "apiKey")
+		assert.Len(t, components["schemas"], 1)
+		assert.Equal(t, []any{map[string]any{"name": "Billing", "description": "Billing operations"}}, doc["tags"])
+		assert.Equal(t, "Test API Mount", doc["info"].(map[string]any)["title"])
+
+		schema, err := r.MarshalYAML()
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(schema), "openapi: 3.0.3\ninfo:"), "expected the field order of the spec package")
+		assert.Contains(t, string(schema), "/api/billing/invoices:")
+	})
+	t.Run("follows the mounted routes", func(t *testing.T) {
+		r := fiberopenapi.NewRouter(fiber.New())
+		billingApp, billing := newBilling()
+		r.Mount("/billing", billing)
+		_ = getJSON(t, r)
+
+		billing.Get("/refunds", PingHandler).With(option.Summary("List refunds"))
+		assert.Contains(t, getJSON(t, r)["paths"], "/billing/refunds")
+
+		req, _ := http.NewRequest("GET", "/refunds", nil)
+		res, err := billingApp.Test(req, -1)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		_ = res.Body.Close()
+	})
+	t.Run("detects collisions", func(t *testing.T) {
+		r := fiberopenapi.NewRouter(fiber.New(),
+			option.WithSecurity("apiKey", option.SecurityAPIKey("api_key", openapi.SecuritySchemeAPIKeyInQuery)),
+		)
+		r.Get("/billing/invoices", PingHandler).With(option.Summary("Legacy invoices"))
+		r.Get("/invoices", PingHandler).With(option.OperationID("listInvoices"))
+		_, billing := newBilling()
+		r.Mount("/billing", billing)
+
+		err := r.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "mount /billing: GET /billing/invoices is already documented by the router")
+		assert.Contains(t, err.Error(), "mount /billing: component securitySchemes/apiKey is defined differently by the router")
+		assert.Contains(t, err.Error(), `mount /billing: duplicate operationId "listInvoices"`)
+
+		_, err = r.MarshalYAML()
+		assert.Error(t, err)
+	})
+	t.Run("requires a generator on a fiber app", func(t *testing.T) {
+		r := fiberopenapi.NewRouter(fiber.New())
+		sub := fiberopenapi.NewRouter(fiber.New().Group("/v1"))
+		assert.Panics(t, func() { r.Mount("/v1", sub) })
+		assert.Panics(t, func() { r.Group("/api").Mount("/self", r) })
+	})
+	t.Run("rejects mount cycles", func(t *testing.T) {
+		a := fiberopenapi.NewRouter(fiber.New(), option.WithDisableDocs())
+		b := fiberopenapi.NewRouter(fiber.New(), option.WithDisableDocs())
+		c := fiberopenapi.NewRouter(fiber.New(), option.WithDisableDocs())
+		a.Mount("/b", b)
+		b.Mount("/c", c)
+
+		assert.PanicsWithValue(t,
+			"fiberopenapi: Mount can not mount a router on itself, directly or through the generators mounted on it",
+			func() { c.Mount("/a", a) })
+		assert.NoError(t, a.Validate(), "expected the rejected mount to leave the routers unchanged")
+	})
+}
+
+func TestFromApp(t *testing.T) {
+	type User struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	auth := func(c *fiber.Ctx) error { return c.Next() }
+
+	newApp := func() *fiber.App {
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error { return c.Next() })
+		app.Get("/users/:id<int>", PingHandler).Name("getUser")
+		api := app.Group("/api")
+		api.Post("/users", auth, PingHandler)
+		app.Connect("/tunnel", PingHandler)
+		return app
+	}
+	newGenerator := func(app *fiber.App) fiberopenapi.AppGenerator {
+		return fiberopenapi.FromApp(app, fiberopenapi.Config{
+			SecurityMiddleware: []fiberopenapi.SecurityMiddleware{{Middleware: auth, Name: "bearerAuth"}},
+		},
+			option.WithTitle("Test API From App"),
+			option.WithSecurity("bearerAuth", option.SecurityHTTPBearer("Bearer")),
+		)
+	}
+
+	t.Run("documents the app routes", func(t *testing.T) {
+		app := newApp()
+		r := newGenerator(app)
+		r.NamedRoute("getUser").With(
+			option.Summary("Get user"),
+			option.Response(200, new(User)),
+		)
+		r.Operation("post", "/api/users").With(option.Summary("Create user"))
+		r.Get("/ping", PingHandler).With(option.Summary("Ping Endpoint"))
+
+		require.NoError(t, r.Validate())
+		schema, err := r.MarshalJSON()
+		require.NoError(t, err)
+		var doc struct {
+			Paths map[string]map[string]struct {
+				Summary    string                `json:"summary"`
+				Security   []map[string][]string `json:"security"`
+				Parameters []struct {
+					Name string `json:"name"`
+					In   string `json:"in"`
+				} `json:"parameters"`
+			} `json:"paths"`
+		}
+		require.NoError(t, json.Unmarshal(schema, &doc))
+
+		assert.Len(t, doc.Paths, 3)
+		getUser := doc.Paths["/users/{id}"]
+		assert.Len(t, getUser, 1, "expected the HEAD route of the GET route to be skipped")
+		assert.Equal(t, "Get user", getUser["get"].Summary)
+		require.Len(t, getUser["get"].Parameters, 1)
+		assert.Equal(t, "id", getUser["get"].Parameters[0].Name)
+		assert.Equal(t, "Create user", doc.Paths["/api/users"]["post"].Summary)
+		assert.Equal(t, []map[string][]string{{"bearerAuth": {}}}, doc.Paths["/api/users"]["post"].Security)
+		assert.Contains(t, doc.Paths, "/ping")
+		assert.NotContains(t, doc.Paths, "/tunnel")
+
+		req, _ := http.NewRequest("GET", "/docs/openapi.yaml", nil)
+		res, err := app.Test(req, -1)
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		assert.Contains(t, string(body), "/users/{id}:")
+		assert.NotContains(t, string(body), "/docs", "expected the docs routes not to be documented")
+	})
+	t.Run("reports unknown routes", func(t *testing.T) {
+		r := newGenerator(newApp())
+		r.Operation("DELETE", "/users/:id<int>").With(option.Summary("Delete user"))
+		r.NamedRoute("listUsers").With(option.Summary("List users"))
+
+		err := r.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "DELETE /users/:id<int>: no route registered on the app")
+		assert.Contains(t, err.Error(), `no route named "listUsers" registered on the app`)
+	})
+}
+
+func TestRouter_Connect(t *testing.T) {
+	t.Run("documented CONNECT route", func(t *testing.T) {
+		app := fiber.New()
+		r := fiberopenapi.NewRouter(app)
+		r.Connect("/tunnel", PingHandler).With(option.Summary("Tunnel"))
+
+		err := r.Validate()
+		require.Error(t, err, "expected error for documented CONNECT route")
+		assert.Contains(t, err.Error(), "CONNECT /tunnel")
+
+		schema, err := r.MarshalYAML()
+		require.NoError(t, err, "expected the route to be left out of the schema")
+		assert.NotContains(t, string(schema), "/tunnel")
+
+		req, _ := http.NewRequest("GET", "/docs/openapi.yaml", nil)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "expected the docs to be served")
+	})
+	t.Run("hidden CONNECT route", func(t *testing.T) {
+		app := fiber.New()
+		r := fiberopenapi.NewRouter(app)
+		r.Connect("/tunnel", PingHandler).With(option.Hide())
+		r.Group("/proxy").With(option.GroupHide()).Connect("/:host", PingHandler)
+		r.Get("/ping", PingHandler)
+
+		require.NoError(t, r.Validate())
+		schema, err := r.MarshalYAML()
+		require.NoError(t, err)
+		assert.Contains(t, string(schema), "/ping")
+		assert.NotContains(t, string(schema), "/tunnel")
+	})
+}
+
+func TestRouter_SubRouterGenerator(t *testing.T) {
+	app := fiber.New()
+	r := fiberopenapi.NewRouter(app, option.WithTitle("Test API Sub-Router Generator"))
+	r.Get("/ping", PingHandler).With(option.Summary("Ping Endpoint"))
+
+	var routed fiberopenapi.Router
+	api := r.Group("/api")
+	api.Route("/v1", func(r fiberopenapi.Router) {
+		routed = r
+		r.Get("/pets", PingHandler).With(option.Summary("List pets"))
+	})
+
+	for name, sub := range map[string]fiberopenapi.Router{"group": api, "route": routed} {
+		t.Run(name, func(t *testing.T) {
+			assert.Same(t, r, sub.Generator(), "expected sub-router to return the root generator")
+
+			gen, ok := sub.(fiberopenapi.Generator)
+			require.True(t, ok, "expected sub-router to implement Generator")
+			require.NoError(t, gen.Validate())
+
+			schema, err := gen.MarshalYAML()
+			require.NoError(t, err)
+			assert.Contains(t, string(schema), "/ping")
+			assert.Contains(t, string(schema), "/api/v1/pets")
+
+			schema, err = sub.Generator().GenerateOpenAPISchema("json")
+			require.NoError(t, err)
+			assert.Contains(t, string(schema), "/api/v1/pets")
+		})
+	}
+}
+
+func TestRouter_LiveSpec(t *testing.T) {
+	getSpec := func(t *testing.T, app *fiber.App) (int, string) {
+		t.Helper()
+		req, _ := http.NewRequest("GET", "/docs/openapi.yaml", nil)
+		res, err := app.Test(req, -1)
+		require.NoError(t, err, "failed to test OpenAPI YAML route")
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err, "failed to read response body for OpenAPI YAML route")
+		_ = res.Body.Close()
+		return res.StatusCode, string(body)
+	}
+
+	app := fiber.New()
+	r := fiberopenapi.NewRouter(app)
+	r.Get("/ping", PingHandler).With(option.Summary("Ping Endpoint"))
+
+	status, body := getSpec(t, app)
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "/ping")
+	assert.NotContains(t, body, "/pong")
+
+	t.Run("routes added after the first request", func(t *testing.T) {
+		api := r.Group("/api")
+		api.Get("/pong", PingHandler).With(option.Summary("Pong Endpoint"))
+
+		status, body := getSpec(t, app)
+		require.Equal(t, http.StatusOK, status)
+		assert.Contains(t, body, "/api/pong")
+	})
+	t.Run("options added after the first request", func(t *testing.T) {
+		r.With(option.GroupTags("ping"))
+
+		status, body := getSpec(t, app)
+		require.Equal(t, http.StatusOK, status)
+		assert.Contains(t, body, "- ping")
+	})
+	t.Run("errors do not stick", func(t *testing.T) {
+		broken := r.Get("/broken", PingHandler).With(
+			option.Request(new(struct {
+				A string `query:"a"`
+				B string `query:"a"`
+			})),
+		)
+		status, _ := getSpec(t, app)
+		assert.Equal(t, http.StatusInternalServerError, status)
+		require.Error(t, r.Validate())
+
+		broken.With(option.Hide())
+		status, _ = getSpec(t, app)
+		assert.Equal(t, http.StatusOK, status)
+		require.NoError(t, r.Validate())
+	})
+	t.Run("concurrent requests and updates", func(t *testing.T) {
+		routes := make([]fiberopenapi.Route, 10)
+		for i := range routes {
+			routes[i] = r.Get(fmt.Sprintf("/concurrent/%d", i), PingHandler)
+		}
+
+		var wg sync.WaitGroup
+		for i := range routes {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				routes[i].With(option.Summary(fmt.Sprintf("Concurrent %d", i)))
+			}(i)
+			go func() {
+				defer wg.Done()
+				status, _ := getSpec(t, app)
+				assert.Equal(t, http.StatusOK, status)
+			}()
+		}
+		wg.Wait()
+
+		status, body := getSpec(t, app)
+		require.Equal(t, http.StatusOK, status)
+		for i := range routes {
+			assert.Contains(t, body, fmt.Sprintf("summary: Concurrent %d", i))
+		}
+	})
 }
 
 func TestGenerator_DisableDocs(t *testing.T) {
@@ -0,0 +1,164 @@
+package fiberopenapi
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/spec/option"
+)
+
+// SecurityMiddleware maps a middleware to the security requirement it enforces,
+// see Config.SecurityMiddleware.
+type SecurityMiddleware struct {
+	// Middleware is the middleware enforcing the requirement. It is matched
+	// by identity, so routes and groups must be given the same value. A
+	// handler that is not the same value, such as a method value like
+	// auth.Handle evaluated again, matches by its function when no other
+	// middleware of the mappings shares that function.
+	Middleware fiber.Handler
+
+	// Name is the name of the security scheme, declared with option.WithSecurity.
+	Name string
+
+	// Scopes are the scopes required by the middleware.
+	Scopes []string
+}
+
+// middlewareSecurity finds the security requirements enforced by middleware.
+type middlewareSecurity struct {
+	mappings []SecurityMiddleware
+	byValue  map[uintptr]int // index of the mapping of each handler value
+	byCode   map[uintptr]int // index of the mapping of each function, -1 when shared
+
+	mu      sync.Mutex
+	matched []bool // mappings found in the handlers of a route or group, see unmatchedErrors
+}
+
+func newMiddlewareSecurity(mappings []SecurityMiddleware) *middlewareSecurity {
+	s := &middlewareSecurity{
+		mappings: mappings,
+		byValue:  make(map[uintptr]int, len(mappings)),
+		byCode:   make(map[uintptr]int, len(mappings)),
+		matched:  make([]bool, len(mappings)),
+	}
+	for i, m := range mappings {
+		if m.Middleware == nil {
+			continue
+		}
+		if _, dup := s.byValue[handlerID(m.Middleware)]; !dup {
+			s.byValue[handlerID(m.Middleware)] = i
+		}
+		code := handlerCode(m.Middleware)
+		j, dup := s.byCode[code]
+		switch {
+		case !dup:
+			s.byCode[code] = i
+		case j >= 0 && handlerID(mappings[j].Middleware) != handlerID(m.Middleware):
+			s.byCode[code] = -1
+		}
+	}
+	return s
+}
+
+// find returns the index of the mapping of the handler.
+func (s *middlewareSecurity) find(h fiber.Handler) (int, bool) {
+	if i, ok := s.byValue[handlerID(h)]; ok {
+		return i, true
+	}
+	if i, ok := s.byCode[handlerCode(h)]; ok && i >= 0 {
+		return i, true
+	}
+	return 0, false
+}
+
+// lookup returns the requirements enforced by the handlers, in their order.
+func (s *middlewareSecurity) lookup(handlers []fiber.Handler) []SecurityMiddleware {
+	if len(s.mappings) == 0 {
+		return nil
+	}
+	var found []SecurityMiddleware
+	for _, h := range handlers {
+		if h == nil {
+			continue
+		}
+		if i, ok := s.find(h); ok {
+			s.mu.Lock()
+			s.matched[i] = true
+			s.mu.Unlock()
+			found = append(found, s.mappings[i])
+		}
+	}
+	return found
+}
+
+// unmatchedErrors returns an error for each mapping whose middleware was not
+// found in the handlers of any route or group, located at source.
+func (s *middlewareSecurity) unmatchedErrors(source SourceLocation) []error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for i, m := range s.mappings {
+		if m.Middleware == nil || s.matched[i] {
+			continue
+		}
+		errs = append(errs, &SourceError{
+			Source: source,
+			Err:    fmt.Errorf("security middleware %d (%s) is not used by any route or group", i, m.Name),
+		})
+	}
+	return errs
+}
+
+// groupOptions returns the group options documenting the requirements enforced by the handlers.
+func (s *middlewareSecurity) groupOptions(handlers []fiber.Handler) []option.GroupOption {
+	var opts []option.GroupOption
+	for _, m := range s.lookup(handlers) {
+		opts = append(opts, option.GroupSecurity(m.Name, m.Scopes...))
+	}
+	return opts
+}
+
+// operationOptions returns the operation options documenting the requirements enforced by the handlers.
+func (s *middlewareSecurity) operationOptions(handlers []fiber.Handler) []option.OperationOption {
+	var opts []option.OperationOption
+	for _, m := range s.lookup(handlers) {
+		opts = append(opts, option.Security(m.Name, m.Scopes...))
+	}
+	return opts
+}
+
+// handlerID returns the identity of a handler value.
+//
+// A func value points to the closure it was created as, so copies of a
+// middleware share their identity while two middleware created by the same
+// constructor, with different settings, do not.
+func handlerID(h fiber.Handler) uintptr {
+	return *(*uintptr)(unsafe.Pointer(&h))
+}
+
+// handlerCode returns the function of a handler value, shared by all the
+// closures and method values created from the same code.
+func handlerCode(h fiber.Handler) uintptr {
+	return reflect.ValueOf(h).Pointer()
+}
+
+// useHandlers returns the middleware passed to Use, or nil when Use is given
+// a prefix and the middleware does not apply to the whole router.
+func useHandlers(args []any) []fiber.Handler {
+	var handlers []fiber.Handler
+	for _, arg := range args {
+		switch a := arg.(type) {
+		case fiber.Handler:
+			handlers = append(handlers, a)
+		case []fiber.Handler:
+			handlers = append(handlers, a...)
+		case string, []string:
+			return nil
+		}
+	}
+	return handlers
+}
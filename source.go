@@ -0,0 +1,119 @@
+package fiberopenapi
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SourceLocation is a location in the source code, such as the call that
+// registered a route.
+type SourceLocation struct {
+	File string
+	Line int
+}
+
+func (l SourceLocation) String() string {
+	if l.File == "" {
+		return ""
+	}
+	return l.File + ":" + strconv.Itoa(l.Line)
+}
+
+// pkgPrefix prefixes the names of the functions of the package.
+var pkgPrefix = reflect.TypeOf(router{}).PkgPath() + "."
+
+// callerLocation returns the location of the first caller outside of the package.
+func callerLocation() SourceLocation {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, pkgPrefix) {
+			return SourceLocation{File: frame.File, Line: frame.Line}
+		}
+		if !more {
+			return SourceLocation{}
+		}
+	}
+}
+
+// handlerLocation returns the location of the last handler, for the routes
+// registered directly on Fiber whose registration is not known.
+func handlerLocation(handlers []fiber.Handler) SourceLocation {
+	if len(handlers) == 0 {
+		return SourceLocation{}
+	}
+	fn := runtime.FuncForPC(reflect.ValueOf(handlers[len(handlers)-1]).Pointer())
+	if fn == nil {
+		return SourceLocation{}
+	}
+	file, line := fn.FileLine(fn.Entry())
+	return SourceLocation{File: file, Line: line}
+}
+
+// SourceError is an error caused by a route registration, reported by Validate.
+type SourceError struct {
+	// Source is the location of the registration.
+	Source SourceLocation
+	// With are the locations of the With calls of the route that are not on
+	// the line of the registration.
+	With []SourceLocation
+	Err  error
+}
+
+func (e *SourceError) Error() string {
+	msg := e.Err.Error()
+	if e.Source.File != "" {
+		msg = e.Source.String() + ": " + msg
+	}
+	if len(e.With) > 0 {
+		locations := make([]string, 0, len(e.With))
+		for _, l := range e.With {
+			locations = append(locations, l.String())
+		}
+		msg += " (options at " + strings.Join(locations, ", ") + ")"
+	}
+	return msg
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.Err
+}
+
+// sourceKey identifies an operation of the specification for its x-source extension.
+func sourceKey(method, path string) string {
+	return strings.ToLower(method) + " " + path
+}
+
+// addSourceExtensions adds the x-source extension to the operations of doc
+// registered at the sources.
+func addSourceExtensions(doc map[string]any, sources map[string]SourceLocation) {
+	if len(sources) == 0 {
+		return
+	}
+	wd, _ := os.Getwd()
+	paths, _ := doc["paths"].(map[string]any)
+	for path, item := range paths {
+		item, _ := item.(map[string]any)
+		for method, op := range item {
+			op, ok := op.(map[string]any)
+			source, found := sources[sourceKey(method, path)]
+			if !ok || !found {
+				continue
+			}
+			if wd != "" {
+				if rel, err := filepath.Rel(wd, source.File); err == nil && !strings.HasPrefix(rel, "..") {
+					source.File = filepath.ToSlash(rel)
+				}
+			}
+			op["x-source"] = source.String()
+		}
+	}
+}
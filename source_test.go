@@ -0,0 +1,87 @@
+package fiberopenapi_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi"
+	"github.com/oaswrap/spec/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_SourceLocations(t *testing.T) {
+	type StreamRequest struct {
+		Events chan string `json:"events"`
+	}
+
+	t.Run("router options", func(t *testing.T) {
+		r := fiberopenapi.NewRouter(fiber.New(), option.WithOpenAPIVersion("2.0"))
+		r.Get("/ping", PingHandler)
+
+		err := r.Validate()
+		require.Error(t, err)
+		var sourceErr *fiberopenapi.SourceError
+		require.ErrorAs(t, err, &sourceErr)
+		assert.Equal(t, "source_test.go", filepath.Base(sourceErr.Source.File))
+		assert.EqualError(t, sourceErr.Unwrap(), "unsupported OpenAPI version: 2.0")
+		assert.Regexp(t, `^.*source_test\.go:\d+: unsupported OpenAPI version: 2\.0$`, err.Error())
+	})
+
+	t.Run("routes", func(t *testing.T) {
+		r := fiberopenapi.NewRouter(fiber.New())
+		r.Post("/stream", PingHandler).
+			With(option.Summary("Stream events")).
+			With(option.Request(new(StreamRequest)))
+		r.Get("/ping", PingHandler)
+		r.Get("/ping", PingHandler).With(option.Summary("Ping again"))
+		r.Connect("/tunnel", PingHandler)
+
+		err := r.Validate()
+		require.Error(t, err)
+		var errs []error
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			errs = joined.Unwrap()
+		}
+		require.Len(t, errs, 3)
+		for _, err := range errs {
+			var sourceErr *fiberopenapi.SourceError
+			require.ErrorAs(t, err, &sourceErr, "expected every error to be located: %v", err)
+			assert.Equal(t, "source_test.go", filepath.Base(sourceErr.Source.File))
+		}
+		assert.Regexp(t, `source_test\.go:\d+: setup request post /stream: events: type is not supported: chan string `+
+			`\(options at .*source_test\.go:\d+, .*source_test\.go:\d+\)`, errs[0].Error())
+		assert.Regexp(t, `source_test\.go:\d+: operation already exists: get /ping$`, errs[1].Error())
+		assert.Regexp(t, `source_test\.go:\d+: CONNECT /tunnel: OpenAPI does not support the CONNECT method`, errs[2].Error())
+
+		var duplicate, connect *fiberopenapi.SourceError
+		require.ErrorAs(t, errs[1], &duplicate)
+		require.ErrorAs(t, errs[2], &connect)
+		assert.Equal(t, connect.Source.Line-1, duplicate.Source.Line, "expected the duplicate route to be located")
+	})
+
+	t.Run("x-source extension", func(t *testing.T) {
+		r := fiberopenapi.NewRouterWithConfig(fiber.New(), fiberopenapi.Config{SourceExtension: true})
+		r.Get("/ping", PingHandler)
+		r.Get("/hidden", PingHandler).With(option.Hide())
+
+		require.NoError(t, r.Validate())
+		schema, err := r.MarshalYAML()
+		require.NoError(t, err)
+		assert.Regexp(t, `(?m)^      x-source: source_test\.go:\d+$`, string(schema))
+		assert.NotContains(t, string(schema), "/hidden")
+	})
+
+	t.Run("audit", func(t *testing.T) {
+		type GetUserRequest struct {
+			ID int `path:"id"`
+		}
+		r := fiberopenapi.NewRouter(fiber.New())
+		r.Get("/users/:userId", PingHandler).With(option.Request(new(GetUserRequest)))
+
+		issues := r.Audit()
+		require.Len(t, issues, 1)
+		assert.Regexp(t, `^.*source_test\.go:\d+: GET /users/:userId: path parameter "id"`, issues[0].String())
+	})
+}
@@ -21,6 +21,10 @@ type Generator interface {
 
 	// WriteSchemaTo writes the OpenAPI schema to a file.
 	WriteSchemaTo(filePath string) error
+
+	// Audit compares the routes registered on Fiber with the documented
+	// operations and returns the inconsistencies found.
+	Audit() []AuditIssue
 }
 
 // Router defines the interface for an OpenAPI router.
@@ -41,6 +45,8 @@ type Router interface {
 	// Delete registers a DELETE route.
 	Delete(path string, handler ...fiber.Handler) Route
 	// Connect registers a CONNECT route.
+	// OpenAPI has no CONNECT operation, so the route is left out of the
+	// specification and Validate reports an error unless the route is hidden.
 	Connect(path string, handler ...fiber.Handler) Route
 	// Options registers an OPTIONS route.
 	Options(path string, handler ...fiber.Handler) Route
@@ -59,7 +65,17 @@ type Router interface {
 	// Route creates a new sub-router with the specified prefix and applies options.
 	Route(prefix string, fn func(router Router)) Router
 
+	// Mount mounts the Fiber app of sub under the prefix and merges the
+	// paths, components, tags and security schemes of sub into the
+	// specification of the router. sub must be created on a *fiber.App and
+	// must not mount the router, directly or through its own mounts.
+	Mount(prefix string, sub Generator) Router
+
 	// With applies options to the router.
 	// This allows you to configure tags, security, and visibility for the routes.
 	With(opts ...option.GroupOption) Router
+
+	// Generator returns the generator of the root router.
+	// Sub-routers created by Group and Route share it with the root router.
+	Generator() Generator
 }
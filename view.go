@@ -0,0 +1,206 @@
+package fiberopenapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi/internal/handler"
+	"github.com/oaswrap/fiberopenapi/internal/jsonref"
+	"github.com/oaswrap/fiberopenapi/internal/util"
+)
+
+// SpecView is the view of the specification served to a request, see
+// Config.SpecView. The zero SpecView is the full specification.
+type SpecView struct {
+	// HideTags hides the operations with one of the tags, for example an
+	// "internal" tag marking the operations of internal users or a tag
+	// marking the operations of a disabled feature. The tags themselves and
+	// the components only used by the hidden operations are hidden as well.
+	HideTags []string
+}
+
+// key returns the key of the view in the cache of the docs handler,
+// empty for the full specification.
+func (v SpecView) key() string {
+	tags := append([]string(nil), v.HideTags...)
+	sort.Strings(tags)
+	var unique []string
+	for i, tag := range tags {
+		if i == 0 || tag != tags[i-1] {
+			unique = append(unique, tag)
+		}
+	}
+	return strings.Join(unique, "\n")
+}
+
+// prune removes the operations, tags and components hidden by the view from doc.
+func (v SpecView) prune(doc map[string]any) {
+	hidden := make(map[string]bool, len(v.HideTags))
+	for _, tag := range v.HideTags {
+		hidden[tag] = true
+	}
+	used := usedComponents(doc)
+
+	paths, _ := doc["paths"].(map[string]any)
+	for path, item := range paths {
+		item, _ := item.(map[string]any)
+		operations := 0
+		for method, op := range item {
+			op, ok := op.(map[string]any)
+			if !ok || !util.IsOperationMethod(method) {
+				continue
+			}
+			if hasTag(op, hidden) {
+				delete(item, method)
+				continue
+			}
+			operations++
+		}
+		if operations == 0 {
+			delete(paths, path)
+		}
+	}
+
+	if tags, ok := doc["tags"].([]any); ok {
+		kept := tags[:0]
+		for _, tag := range tags {
+			tag, _ := tag.(map[string]any)
+			if name, _ := tag["name"].(string); !hidden[name] {
+				kept = append(kept, tag)
+			}
+		}
+		doc["tags"] = kept
+		if len(kept) == 0 {
+			delete(doc, "tags")
+		}
+	}
+
+	// Components that were not used before are left, they are part of the
+	// specification on their own.
+	stillUsed := usedComponents(doc)
+	components, _ := doc["components"].(map[string]any)
+	for ref := range used {
+		if stillUsed[ref] {
+			continue
+		}
+		kind, name, _ := strings.Cut(strings.TrimPrefix(ref, "#/components/"), "/")
+		name = jsonref.Unescape(name)
+		if section, ok := components[kind].(map[string]any); ok {
+			delete(section, name)
+		}
+	}
+}
+
+// hasTag reports whether the operation has one of the tags.
+func hasTag(op map[string]any, tags map[string]bool) bool {
+	opTags, _ := op["tags"].([]any)
+	for _, tag := range opTags {
+		if name, _ := tag.(string); tags[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// usedComponents returns the references to the components of doc that are
+// used outside of the components, directly or through other components.
+func usedComponents(doc map[string]any) map[string]bool {
+	used := make(map[string]bool)
+	var refs []string
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#/components/") && !used[ref] {
+				used[ref] = true
+				refs = append(refs, ref)
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []any:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	for key, value := range doc {
+		if key != "components" {
+			walk(value)
+		}
+	}
+	for len(refs) > 0 {
+		ref := refs[len(refs)-1]
+		refs = refs[:len(refs)-1]
+		if component := jsonref.Resolve(doc, ref); component != nil {
+			walk(component)
+		}
+	}
+	return used
+}
+
+// viewSpec is the specification of a router pruned by a view.
+type viewSpec struct {
+	r    *router
+	view SpecView
+}
+
+// SpecVersion returns the version of the specification of the router.
+func (v viewSpec) SpecVersion() uint64 {
+	return v.r.SpecVersion()
+}
+
+func (v viewSpec) spec() (mergedSpec, error) {
+	schema, err := v.r.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(schema, &doc); err != nil {
+		return nil, err
+	}
+	v.view.prune(doc)
+	return typedSpec(doc)
+}
+
+// MarshalYAML marshals the pruned specification to YAML format.
+func (v viewSpec) MarshalYAML() ([]byte, error) {
+	spec, err := v.spec()
+	if err != nil {
+		return nil, err
+	}
+	return spec.MarshalYAML()
+}
+
+// MarshalJSON marshals the pruned specification to JSON format.
+func (v viewSpec) MarshalJSON() ([]byte, error) {
+	spec, err := v.spec()
+	if err != nil {
+		return nil, err
+	}
+	schema, err := spec.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := json.Indent(&buffer, schema, "", "  "); err != nil {
+		return nil, fmt.Errorf("failed to indent OpenAPI JSON schema: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// specView returns the function selecting the view of the docs handler.
+func (r *router) specView(view func(c *fiber.Ctx) SpecView) func(c *fiber.Ctx) (string, handler.Generator) {
+	return func(c *fiber.Ctx) (string, handler.Generator) {
+		v := view(c)
+		key := v.key()
+		if key == "" {
+			return "", nil
+		}
+		return key, viewSpec{r: r, view: v}
+	}
+}
@@ -0,0 +1,94 @@
+package fiberopenapi_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oaswrap/fiberopenapi"
+	"github.com/oaswrap/spec/openapi"
+	"github.com/oaswrap/spec/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type AuditLog struct {
+	Entries []AuditEntry `json:"entries"`
+}
+
+type AuditEntry struct {
+	Action string `json:"action"`
+}
+
+func TestRouter_SpecView(t *testing.T) {
+	app := fiber.New()
+	r := fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{
+		SpecView: func(c *fiber.Ctx) fiberopenapi.SpecView {
+			if c.Get("X-Audience") == "internal" {
+				return fiberopenapi.SpecView{}
+			}
+			return fiberopenapi.SpecView{HideTags: []string{"internal"}}
+		},
+	}, option.WithTags(
+		openapi.Tag{Name: "pets"},
+		openapi.Tag{Name: "internal", Description: "Operations of the staff"},
+	))
+	r.Get("/pets", PingHandler).With(
+		option.Tags("pets"),
+		option.Response(200, new(ErrorResponse)),
+	)
+	r.Delete("/pets", PingHandler).With(
+		option.Tags("pets", "internal"),
+		option.Response(400, new(ErrorResponse)),
+	)
+	admin := r.Group("/admin").With(option.GroupTags("internal"))
+	admin.Get("/audit", PingHandler).With(option.Response(200, new(AuditLog)))
+
+	get := func(t *testing.T, audience string) string {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/docs/openapi.yaml", nil)
+		req.Header.Set("X-Audience", audience)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, 200, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	public := get(t, "partner")
+	assert.Contains(t, public, "/pets:\n    get:")
+	assert.NotContains(t, public, "delete:")
+	assert.NotContains(t, public, "/admin/audit")
+	assert.NotContains(t, public, "Operations of the staff")
+	assert.NotContains(t, public, "AuditLog", "expected the components of the hidden operations to be hidden")
+	assert.NotContains(t, public, "AuditEntry")
+	assert.Contains(t, public, "FiberopenapiTestErrorResponse", "expected the shared components to be kept")
+
+	internal := get(t, "internal")
+	full, err := r.MarshalYAML()
+	require.NoError(t, err)
+	assert.Equal(t, string(full), internal)
+
+	r.Get("/pets/:id", PingHandler).With(option.Tags("pets"))
+	assert.Contains(t, get(t, "partner"), "/pets/{id}:", "expected the view to follow the routes")
+}
+
+func TestRouter_SpecViewCacheControl(t *testing.T) {
+	app := fiber.New()
+	r := fiberopenapi.NewRouterWithConfig(app, fiberopenapi.Config{
+		SpecCacheControl: "public, max-age=600",
+		SpecView: func(c *fiber.Ctx) fiberopenapi.SpecView {
+			return fiberopenapi.SpecView{}
+		},
+	})
+	r.Get("/pets", PingHandler)
+
+	req := httptest.NewRequest("GET", "/docs/openapi.yaml", nil)
+	req.Header.Set("X-Audience", "internal")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, "private, max-age=600", resp.Header.Get(fiber.HeaderCacheControl),
+		"expected the full specification not to be stored by shared caches")
+}